@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/JustinLi007/rss-aggregator/internal/auth"
+	"github.com/JustinLi007/rss-aggregator/internal/database"
+	"github.com/google/uuid"
+)
+
+// bootstrapAdmin makes the password account name an admin while the instance
+// has no active admin, creating the account with password if it doesn't
+// exist yet. Once an admin exists it does nothing, so the variables can stay
+// set. API keys of a promoted account keep their scopes; the admin scope
+// comes with logging in or with keys created afterwards.
+func bootstrapAdmin(ctx context.Context, db *database.Queries, name, password string) error {
+	if name == "" {
+		return nil
+	}
+
+	count, err := db.CountAdmins(ctx)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+
+	user, err := db.GetUserByName(ctx, name)
+	if errors.Is(err, sql.ErrNoRows) {
+		if password == "" {
+			return fmt.Errorf("No password account named %q; set ADMIN_BOOTSTRAP_PASSWORD to create it", name)
+		}
+		hash, err := auth.HashPassword(password)
+		if err != nil {
+			return err
+		}
+		user, err = db.CreateUser(ctx, database.CreateUserParams{
+			ID:           uuid.New(),
+			CreatedAt:    time.Now().UTC(),
+			UpdatedAt:    time.Now().UTC(),
+			Name:         name,
+			PasswordHash: sql.NullString{String: hash, Valid: true},
+		})
+		if isDuplicateKeyError(err) {
+			return fmt.Errorf("The name %q belongs to an account without a password", name)
+		}
+		if err != nil {
+			return err
+		}
+	} else if err != nil {
+		return err
+	}
+
+	if user.DisabledAt.Valid {
+		if _, err := db.EnableUser(ctx, user.ID); err != nil {
+			return err
+		}
+	}
+	if err := db.SetUserRole(ctx, database.SetUserRoleParams{
+		ID:   user.ID,
+		Role: roleAdmin,
+	}); err != nil {
+		return err
+	}
+
+	log.Printf("Made %v the first admin", user.Name)
+	return nil
+}
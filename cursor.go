@@ -0,0 +1,67 @@
+package main
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/JustinLi007/rss-aggregator/internal/database"
+	"github.com/google/uuid"
+)
+
+var errInvalidCursor = errors.New("Invalid cursor")
+
+// postCursor identifies a position in a timeline ordered by published_at and
+// id. It is handed to clients as an opaque string.
+type postCursor struct {
+	PublishedAt time.Time
+	ID          uuid.UUID
+}
+
+func (c postCursor) String() string {
+	raw := c.PublishedAt.UTC().Format(time.RFC3339Nano) + "," + c.ID.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func parsePostCursor(s string) (postCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return postCursor{}, errInvalidCursor
+	}
+
+	publishedAt, id, found := strings.Cut(string(raw), ",")
+	if !found {
+		return postCursor{}, errInvalidCursor
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, publishedAt)
+	if err != nil {
+		return postCursor{}, errInvalidCursor
+	}
+
+	postID, err := uuid.Parse(id)
+	if err != nil {
+		return postCursor{}, errInvalidCursor
+	}
+
+	return postCursor{
+		PublishedAt: t,
+		ID:          postID,
+	}, nil
+}
+
+// nextPostCursor returns the cursor of the last post when the page is full,
+// or nil when there is nothing left to fetch.
+func nextPostCursor(posts []database.Post, limit int) *string {
+	if len(posts) == 0 || len(posts) < limit {
+		return nil
+	}
+
+	last := posts[len(posts)-1]
+	cursor := postCursor{
+		PublishedAt: last.PublishedAt,
+		ID:          last.ID,
+	}.String()
+	return &cursor
+}
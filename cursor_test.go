@@ -0,0 +1,34 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestPostCursorRoundTrip(t *testing.T) {
+	expected := postCursor{
+		PublishedAt: time.Date(2024, 7, 1, 12, 30, 15, 123456000, time.UTC),
+		ID:          uuid.New(),
+	}
+
+	actual, err := parsePostCursor(expected.String())
+	if err != nil {
+		t.Fatalf("Failed to parse cursor: %v", err)
+	}
+	if !actual.PublishedAt.Equal(expected.PublishedAt) {
+		t.Errorf("Expected published_at %v, got %v", expected.PublishedAt, actual.PublishedAt)
+	}
+	if actual.ID != expected.ID {
+		t.Errorf("Expected id %v, got %v", expected.ID, actual.ID)
+	}
+}
+
+func TestParsePostCursorInvalid(t *testing.T) {
+	for _, s := range []string{"", "not-base64!", "bm8tY29tbWE", "MjAyNC0wNy0wMSxub3QtYS11dWlk"} {
+		if _, err := parsePostCursor(s); err != errInvalidCursor {
+			t.Errorf("parsePostCursor(%q): expected errInvalidCursor, got %v", s, err)
+		}
+	}
+}
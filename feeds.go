@@ -0,0 +1,156 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/JustinLi007/rss-aggregator/internal/database"
+	"github.com/JustinLi007/rss-aggregator/internal/feedurl"
+	"github.com/google/uuid"
+)
+
+const (
+	roleUser  = "user"
+	roleAdmin = "admin"
+)
+
+// canManageFeed reports whether user may edit or delete feed: only its
+// creator, or current owner after a transfer, and admins can. Admins need a
+// key with the admin scope to act on other users' feeds.
+func canManageFeed(ctx context.Context, user database.User, feed database.Feed) bool {
+	if feed.UserID == user.ID {
+		return true
+	}
+	return user.Role == roleAdmin && slices.Contains(scopesFromContext(ctx), scopeAdmin)
+}
+
+// getOrCreateFeed normalizes rawURL and returns the feed already registered
+// under the same canonical URL, creating it for user when none exists. The
+// boolean reports whether a new feed was created.
+func (cfg *apiConfig) getOrCreateFeed(ctx context.Context, user database.User, name, rawURL string) (database.Feed, bool, error) {
+	feedURL, err := feedurl.Normalize(rawURL)
+	if err != nil {
+		return database.Feed{}, false, err
+	}
+	canonicalURL := feedurl.Canonical(feedURL)
+
+	feed, err := cfg.DB.GetFeedByCanonicalURL(ctx, canonicalURL)
+	if err == nil {
+		return feed, false, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return database.Feed{}, false, err
+	}
+
+	feed, err = cfg.DB.CreateFeed(ctx, database.CreateFeedParams{
+		ID:           uuid.New(),
+		CreatedAt:    time.Now().UTC(),
+		UpdatedAt:    time.Now().UTC(),
+		Name:         name,
+		Url:          feedURL,
+		UserID:       user.ID,
+		CanonicalUrl: canonicalURL,
+	})
+	if err != nil {
+		// Another request may have registered the same feed in the meantime.
+		if isDuplicateKeyError(err) {
+			feed, err = cfg.DB.GetFeedByCanonicalURL(ctx, canonicalURL)
+			return feed, false, err
+		}
+		return database.Feed{}, false, err
+	}
+
+	return feed, true, nil
+}
+
+// followFeed makes user follow feedID, returning the existing follow when the
+// user already follows the feed. The boolean reports whether a new follow was
+// created.
+func (cfg *apiConfig) followFeed(ctx context.Context, user database.User, feedID uuid.UUID) (database.UsersFeedsFollow, bool, error) {
+	feedFollow, err := cfg.DB.GetFeedFollowByUserAndFeed(ctx, database.GetFeedFollowByUserAndFeedParams{
+		UserID: user.ID,
+		FeedID: feedID,
+	})
+	if err == nil {
+		return feedFollow, false, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return database.UsersFeedsFollow{}, false, err
+	}
+
+	feedFollow, err = cfg.DB.FollowFeed(ctx, database.FollowFeedParams{
+		ID:        uuid.New(),
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+		UserID:    user.ID,
+		FeedID:    feedID,
+	})
+	if err != nil {
+		// A concurrent request may have followed the feed in the meantime.
+		if isDuplicateKeyError(err) {
+			feedFollow, err = cfg.DB.GetFeedFollowByUserAndFeed(ctx, database.GetFeedFollowByUserAndFeedParams{
+				UserID: user.ID,
+				FeedID: feedID,
+			})
+			return feedFollow, false, err
+		}
+		return database.UsersFeedsFollow{}, false, err
+	}
+
+	// The feed may have gone dormant while nobody followed it.
+	if err := cfg.DB.ResumeFeed(ctx, feedID); err != nil {
+		return database.UsersFeedsFollow{}, false, err
+	}
+
+	return feedFollow, true, nil
+}
+
+// unfollowFeed removes the user's follow of feedID, if any.
+func unfollowFeed(ctx context.Context, db *database.Queries, user database.User, feedID uuid.UUID) error {
+	feedFollow, err := db.GetFeedFollowByUserAndFeed(ctx, database.GetFeedFollowByUserAndFeedParams{
+		UserID: user.ID,
+		FeedID: feedID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	return db.UnfollowFeed(ctx, database.UnfollowFeedParams{
+		ID:     feedFollow.ID,
+		UserID: user.ID,
+	})
+}
+
+// getOrCreateFolder returns the user's folder called name, creating it when
+// it doesn't exist yet.
+func (cfg *apiConfig) getOrCreateFolder(ctx context.Context, user database.User, name string) (database.Folder, error) {
+	folder, err := cfg.DB.GetFolderByName(ctx, database.GetFolderByNameParams{
+		UserID: user.ID,
+		Name:   name,
+	})
+	if err == nil {
+		return folder, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return database.Folder{}, err
+	}
+
+	return cfg.DB.CreateFolder(ctx, database.CreateFolderParams{
+		ID:        uuid.New(),
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+		Name:      name,
+		UserID:    user.ID,
+	})
+}
+
+func isDuplicateKeyError(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "pq: duplicate key value violates")
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/JustinLi007/rss-aggregator/internal/database"
+	"github.com/JustinLi007/rss-aggregator/internal/rules"
+	"github.com/google/uuid"
+)
+
+type compiledFilterRule struct {
+	rule    database.FilterRule
+	matcher *rules.Matcher
+}
+
+func databaseFilterRuleToRule(rule database.FilterRule) (rules.Rule, error) {
+	result := rules.Rule{
+		MatchAll: rule.MatchAll,
+		Tag:      rule.Tag.String,
+	}
+	if err := json.Unmarshal(rule.Conditions, &result.Conditions); err != nil {
+		return rules.Rule{}, err
+	}
+	for _, action := range rule.Actions {
+		result.Actions = append(result.Actions, rules.Action(action))
+	}
+	return result, nil
+}
+
+func compileFilterRule(rule database.FilterRule) (compiledFilterRule, error) {
+	r, err := databaseFilterRuleToRule(rule)
+	if err != nil {
+		return compiledFilterRule{}, err
+	}
+	matcher, err := rules.Compile(r)
+	if err != nil {
+		return compiledFilterRule{}, err
+	}
+	return compiledFilterRule{rule: rule, matcher: matcher}, nil
+}
+
+// compileFeedFilterRules loads the enabled rules of everyone following the
+// feed. Rules that fail to compile are logged and skipped.
+func compileFeedFilterRules(ctx context.Context, db *database.Queries, feed Feed) []compiledFilterRule {
+	filterRules, err := db.GetEnabledFilterRulesForFeed(ctx, feed.ID)
+	if err != nil {
+		log.Printf("Failed to load filter rules of feed %v: %v", feed.Name, err)
+		return nil
+	}
+
+	result := make([]compiledFilterRule, 0, len(filterRules))
+	for _, v := range filterRules {
+		compiled, err := compileFilterRule(v)
+		if err != nil {
+			log.Printf("Failed to compile filter rule %v: %v", v.ID, err)
+			continue
+		}
+		result = append(result, compiled)
+	}
+	return result
+}
+
+func databasePostToRulesPost(post database.Post, feedName string) rules.Post {
+	result := rules.Post{
+		Title:       post.Title,
+		Description: post.Description.String,
+		Content:     post.Content.String,
+		Author:      post.Author.String,
+		Categories:  post.Categories,
+		FeedName:    feedName,
+		URL:         post.Url,
+	}
+	if post.FeedID.Valid {
+		result.FeedID = post.FeedID.UUID.String()
+	}
+	return result
+}
+
+// applyFilterRule runs the rule's actions on a post on behalf of the rule's
+// owner. Every action is idempotent, so rules can be applied again safely.
+func applyFilterRule(ctx context.Context, db *database.Queries, rule database.FilterRule, post database.Post) error {
+	for _, action := range rule.Actions {
+		var err error
+		switch rules.Action(action) {
+		case rules.ActionHide:
+			_, err = db.HidePost(ctx, database.HidePostParams{
+				UserID: rule.UserID,
+				PostID: post.ID,
+			})
+		case rules.ActionRead:
+			_, err = db.MarkPostsRead(ctx, database.MarkPostsReadParams{
+				UserID:  rule.UserID,
+				PostIds: []uuid.UUID{post.ID},
+			})
+		case rules.ActionStar:
+			_, err = db.StarPost(ctx, database.StarPostParams{
+				UserID: rule.UserID,
+				PostID: post.ID,
+			})
+		case rules.ActionTag:
+			var label database.Label
+			label, err = db.UpsertLabel(ctx, database.UpsertLabelParams{
+				ID:        uuid.New(),
+				CreatedAt: time.Now().UTC(),
+				UpdatedAt: time.Now().UTC(),
+				Name:      strings.TrimSpace(rule.Tag.String),
+				UserID:    rule.UserID,
+			})
+			if err == nil {
+				_, err = db.AddPostLabel(ctx, database.AddPostLabelParams{
+					LabelID:   label.ID,
+					PostID:    post.ID,
+					CreatedAt: time.Now().UTC(),
+				})
+			}
+		case rules.ActionNotify:
+			var notify bool
+			notify, err = followNotifies(ctx, db, rule.UserID, post.FeedID)
+			if err != nil || !notify {
+				break
+			}
+			_, err = db.CreateNotification(ctx, database.CreateNotificationParams{
+				ID:           uuid.New(),
+				CreatedAt:    time.Now().UTC(),
+				UserID:       rule.UserID,
+				PostID:       post.ID,
+				FilterRuleID: uuid.NullUUID{UUID: rule.ID, Valid: true},
+			})
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// followNotifies reports whether the user's follow of the feed lets notify
+// rules notify: it must exist, not be muted and not have notifications set to
+// "none".
+func followNotifies(ctx context.Context, db *database.Queries, userID uuid.UUID, feedID uuid.NullUUID) (bool, error) {
+	if !feedID.Valid {
+		return false, nil
+	}
+	follow, err := db.GetFeedFollowByUserAndFeed(ctx, database.GetFeedFollowByUserAndFeedParams{
+		UserID: userID,
+		FeedID: feedID.UUID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return !follow.Muted && follow.Notifications != notificationsNone, nil
+}
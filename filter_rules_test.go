@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/JustinLi007/rss-aggregator/internal/database"
+	"github.com/JustinLi007/rss-aggregator/internal/rules"
+	"github.com/google/uuid"
+)
+
+func TestNotifyRuleNotifies(t *testing.T) {
+	_, db := testDB(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		muted         sql.NullBool
+		notifications sql.NullString
+		expected      int
+	}{
+		// The follow's default settings.
+		{sql.NullBool{}, sql.NullString{}, 1},
+		{sql.NullBool{}, sql.NullString{String: notificationsAll, Valid: true}, 1},
+		{sql.NullBool{}, sql.NullString{String: notificationsNone, Valid: true}, 0},
+		{sql.NullBool{Bool: true, Valid: true}, sql.NullString{}, 0},
+	}
+
+	for _, test := range tests {
+		user, feed := createTestFeed(t, db)
+		follow, err := db.GetFeedFollowByUserAndFeed(ctx, database.GetFeedFollowByUserAndFeedParams{
+			UserID: user.ID,
+			FeedID: feed.ID,
+		})
+		if err != nil {
+			t.Fatalf("Failed to retrieve follow: %v", err)
+		}
+		if _, err := db.UpdateFeedFollowSettings(ctx, database.UpdateFeedFollowSettingsParams{
+			Muted:         test.muted,
+			Notifications: test.notifications,
+			ID:            follow.ID,
+			UserID:        user.ID,
+		}); err != nil {
+			t.Fatalf("Failed to update follow: %v", err)
+		}
+
+		conditions, err := json.Marshal([]rules.Condition{{Field: rules.FieldTitle, Operator: rules.OperatorContains, Value: "post"}})
+		if err != nil {
+			t.Fatalf("Failed to encode conditions: %v", err)
+		}
+		rule, err := db.CreateFilterRule(ctx, database.CreateFilterRuleParams{
+			ID:         uuid.New(),
+			CreatedAt:  time.Now().UTC(),
+			UpdatedAt:  time.Now().UTC(),
+			Name:       "Notify",
+			Conditions: conditions,
+			MatchAll:   true,
+			Actions:    []string{string(rules.ActionNotify)},
+			Enabled:    true,
+			UserID:     user.ID,
+		})
+		if err != nil {
+			t.Fatalf("Failed to create filter rule: %v", err)
+		}
+
+		post := createTestPosts(t, db, feed, time.Hour)[0]
+		if err := applyFilterRule(ctx, db, rule, post); err != nil {
+			t.Fatalf("Failed to apply filter rule: %v", err)
+		}
+
+		notifications, err := db.GetNotificationsByUser(ctx, database.GetNotificationsByUserParams{
+			UserID: user.ID,
+			Limit:  10,
+		})
+		if err != nil {
+			t.Fatalf("Failed to retrieve notifications: %v", err)
+		}
+		if len(notifications) != test.expected {
+			t.Errorf("Muted %v, notifications %q: expected %v notifications, got %v", test.muted.Bool, test.notifications.String, test.expected, len(notifications))
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/JustinLi007/rss-aggregator/internal/database"
+)
+
+// defaultFeedGCGracePeriod is how long a feed nobody follows is kept before
+// it is purged, unless FEED_GC_GRACE_PERIOD says otherwise.
+const defaultFeedGCGracePeriod = 30 * 24 * time.Hour
+
+func initFeedGC(db *database.Queries, interval, gracePeriod time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for ; ; <-ticker.C {
+		collectOrphanedFeeds(context.Background(), db, gracePeriod)
+	}
+}
+
+// collectOrphanedFeeds marks feeds without followers as dormant and purges
+// the ones that stayed dormant for longer than gracePeriod. Dormant feeds
+// are no longer fetched; following one again makes it active right away.
+// Purging a feed keeps the posts somebody starred.
+func collectOrphanedFeeds(ctx context.Context, db *database.Queries, gracePeriod time.Duration) {
+	resumed, err := db.ResumeFollowedFeeds(ctx)
+	if err != nil {
+		log.Printf("Failed to resume followed feeds: %v", err)
+		return
+	}
+
+	dormant, err := db.MarkOrphanedFeedsDormant(ctx)
+	if err != nil {
+		log.Printf("Failed to mark orphaned feeds dormant: %v", err)
+		return
+	}
+
+	purged, err := db.PurgeDormantFeeds(ctx, time.Now().UTC().Add(-gracePeriod))
+	if err != nil {
+		log.Printf("Failed to purge dormant feeds: %v", err)
+		return
+	}
+
+	if resumed > 0 || dormant > 0 || purged > 0 {
+		log.Printf("Feed GC: %v resumed, %v marked dormant, %v purged", resumed, dormant, purged)
+	}
+}
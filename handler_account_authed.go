@@ -0,0 +1,231 @@
+package main
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/JustinLi007/rss-aggregator/internal/auth"
+	"github.com/JustinLi007/rss-aggregator/internal/database"
+	"github.com/google/uuid"
+)
+
+type exportedFollow struct {
+	FeedName string  `json:"feed_name"`
+	Url      string  `json:"url"`
+	SiteUrl  *string `json:"site_url"`
+	Title    *string `json:"title"`
+	Folder   *string `json:"folder"`
+}
+
+type exportedPostState struct {
+	PostID    uuid.UUID  `json:"post_id"`
+	Title     string     `json:"title"`
+	Url       string     `json:"url"`
+	FeedUrl   *string    `json:"feed_url"`
+	ReadAt    *time.Time `json:"read_at"`
+	StarredAt *time.Time `json:"starred_at"`
+	HiddenAt  *time.Time `json:"hidden_at"`
+}
+
+type exportedLabelPost struct {
+	PostID    uuid.UUID `json:"post_id"`
+	Title     string    `json:"title"`
+	Url       string    `json:"url"`
+	LabeledAt time.Time `json:"labeled_at"`
+}
+
+type exportedLabel struct {
+	ID        uuid.UUID           `json:"id"`
+	CreatedAt time.Time           `json:"created_at"`
+	Name      string              `json:"name"`
+	Posts     []exportedLabelPost `json:"posts"`
+}
+
+// handlerExportAccountAuthed returns a zip archive of everything the user
+// has stored: one JSON file per kind of data, plus their follows as OPML.
+func (cfg *apiConfig) handlerExportAccountAuthed(w http.ResponseWriter, r *http.Request, user database.User) {
+	ctx := r.Context()
+
+	feedFollows, err := cfg.DB.GetFeedFollowsForExport(ctx, user.ID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to retrieve feed follows")
+		return
+	}
+	folders, err := cfg.DB.GetFoldersByUser(ctx, user.ID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to retrieve folders")
+		return
+	}
+	postStates, err := cfg.DB.GetPostStatesForExport(ctx, user.ID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to retrieve post states")
+		return
+	}
+	labels, err := cfg.DB.GetLabelsByUser(ctx, user.ID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to retrieve labels")
+		return
+	}
+	postLabels, err := cfg.DB.GetPostLabelsForExport(ctx, user.ID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to retrieve labels")
+		return
+	}
+	filterRules, err := cfg.DB.GetFilterRulesByUser(ctx, user.ID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to retrieve filter rules")
+		return
+	}
+	savedSearches, err := cfg.DB.GetSavedSearchesByUser(ctx, user.ID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to retrieve saved searches")
+		return
+	}
+	apiKeys, err := cfg.DB.GetAPIKeysByUser(ctx, user.ID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to retrieve API keys")
+		return
+	}
+
+	subscriptions, err := buildSubscriptionsOPML(user, feedFollows)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to encode OPML")
+		return
+	}
+
+	follows := make([]exportedFollow, len(feedFollows))
+	for i, v := range feedFollows {
+		follows[i] = exportedFollow{
+			FeedName: v.FeedName,
+			Url:      v.Url,
+			SiteUrl:  nullStringToStringPtr(v.SiteUrl),
+			Title:    nullStringToStringPtr(v.Title),
+			Folder:   nullStringToStringPtr(v.FolderName),
+		}
+	}
+
+	states := make([]exportedPostState, len(postStates))
+	for i, v := range postStates {
+		states[i] = exportedPostState{
+			PostID:    v.PostID,
+			Title:     v.Title,
+			Url:       v.Url,
+			FeedUrl:   nullStringToStringPtr(v.FeedUrl),
+			ReadAt:    nullTimeToTimePtr(v.ReadAt),
+			StarredAt: nullTimeToTimePtr(v.StarredAt),
+			HiddenAt:  nullTimeToTimePtr(v.HiddenAt),
+		}
+	}
+
+	postsByLabel := make(map[uuid.UUID][]exportedLabelPost, len(labels))
+	for _, v := range postLabels {
+		postsByLabel[v.LabelID] = append(postsByLabel[v.LabelID], exportedLabelPost{
+			PostID:    v.PostID,
+			Title:     v.Title,
+			Url:       v.Url,
+			LabeledAt: v.CreatedAt,
+		})
+	}
+	exportedLabels := make([]exportedLabel, len(labels))
+	for i, v := range labels {
+		exportedLabels[i] = exportedLabel{
+			ID:        v.Label.ID,
+			CreatedAt: v.Label.CreatedAt,
+			Name:      v.Label.Name,
+			Posts:     postsByLabel[v.Label.ID],
+		}
+		if exportedLabels[i].Posts == nil {
+			exportedLabels[i].Posts = []exportedLabelPost{}
+		}
+	}
+
+	files := []struct {
+		name string
+		data any
+	}{
+		{"profile.json", databaseUserToUser(user)},
+		{"api_keys.json", databaseAPIKeysToAPIKeys(apiKeys)},
+		{"follows.json", follows},
+		{"folders.json", databaseFoldersToFolders(folders)},
+		{"post_states.json", states},
+		{"labels.json", exportedLabels},
+		{"filter_rules.json", databaseFilterRulesToFilterRules(filterRules)},
+		{"saved_searches.json", databaseSavedSearchesToSavedSearches(savedSearches)},
+	}
+
+	// Everything is loaded before the first byte is written, so a failure
+	// past this point can only cut the archive short.
+	filename := fmt.Sprintf("rss-aggregator-export-%v.zip", time.Now().UTC().Format("2006-01-02"))
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+	w.WriteHeader(http.StatusOK)
+
+	zw := zip.NewWriter(w)
+	defer zw.Close()
+
+	for _, file := range files {
+		fw, err := zw.Create(file.name)
+		if err != nil {
+			return
+		}
+		encoder := json.NewEncoder(fw)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(file.data); err != nil {
+			return
+		}
+	}
+
+	fw, err := zw.Create("subscriptions.opml")
+	if err != nil {
+		return
+	}
+	fw.Write(subscriptions)
+}
+
+// handlerDeleteAccountAuthed deletes the user and everything they own. Feeds
+// other users still follow are handed over to one of them by the
+// users_transfer_owned_feeds trigger before the delete cascades, so only
+// feeds nobody else follows go away. Accounts with a password must confirm
+// it.
+func (cfg *apiConfig) handlerDeleteAccountAuthed(w http.ResponseWriter, r *http.Request, user database.User) {
+	type parameters struct {
+		Password string `json:"password"`
+	}
+
+	if user.PasswordHash.Valid {
+		params := parameters{}
+		if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Failed to decode parameters")
+			return
+		}
+		if err := auth.CheckPassword(user.PasswordHash.String, params.Password); err != nil {
+			respondWithError(w, http.StatusForbidden, "Password is wrong")
+			return
+		}
+	}
+
+	if user.Role == roleAdmin {
+		count, err := cfg.DB.CountAdmins(r.Context())
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Failed to delete account")
+			return
+		}
+		if count <= 1 {
+			respondWithError(w, http.StatusConflict, "Can't delete the last admin account")
+			return
+		}
+	}
+
+	if _, err := cfg.DB.DeleteUser(r.Context(), user.ID); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to delete account")
+		return
+	}
+
+	if _, ok := sessionIDFromContext(r.Context()); ok {
+		clearSessionCookies(w, r)
+	}
+	respondWithJSON(w, http.StatusOK, struct{}{})
+}
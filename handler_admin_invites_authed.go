@@ -0,0 +1,98 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/JustinLi007/rss-aggregator/internal/auth"
+	"github.com/JustinLi007/rss-aggregator/internal/database"
+	"github.com/google/uuid"
+)
+
+// handlerCreateInviteAuthed issues an invite code for invite-only sign ups.
+// max_uses defaults to 1; invites without expires_at stay valid until used
+// up or revoked.
+func (cfg *apiConfig) handlerCreateInviteAuthed(w http.ResponseWriter, r *http.Request, user database.User) {
+	type parameters struct {
+		MaxUses   *int32     `json:"max_uses"`
+		ExpiresAt *time.Time `json:"expires_at"`
+	}
+
+	params := parameters{}
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to decode parameters")
+		return
+	}
+
+	maxUses := int32(1)
+	if params.MaxUses != nil {
+		if *params.MaxUses <= 0 {
+			respondWithError(w, http.StatusBadRequest, "max_uses must be positive")
+			return
+		}
+		maxUses = *params.MaxUses
+	}
+
+	expiresAt := sql.NullTime{}
+	if params.ExpiresAt != nil {
+		if !params.ExpiresAt.After(time.Now()) {
+			respondWithError(w, http.StatusBadRequest, "expires_at must be in the future")
+			return
+		}
+		expiresAt = sql.NullTime{Time: params.ExpiresAt.UTC(), Valid: true}
+	}
+
+	code, err := auth.GenerateToken()
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to generate invite code")
+		return
+	}
+
+	invite, err := cfg.DB.CreateInvite(r.Context(), database.CreateInviteParams{
+		ID:        uuid.New(),
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+		Code:      code,
+		MaxUses:   maxUses,
+		ExpiresAt: expiresAt,
+		CreatedBy: uuid.NullUUID{UUID: user.ID, Valid: true},
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to create invite")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, databaseInviteToInvite(invite))
+}
+
+func (cfg *apiConfig) handlerGetInvitesAuthed(w http.ResponseWriter, r *http.Request, user database.User) {
+	invites, err := cfg.DB.GetInvites(r.Context())
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to retrieve invites")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, databaseInvitesToInvites(invites))
+}
+
+func (cfg *apiConfig) handlerRevokeInviteAuthed(w http.ResponseWriter, r *http.Request, user database.User) {
+	inviteID, err := parseUUIDPathValue(r, "inviteID")
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	n, err := cfg.DB.RevokeInvite(r.Context(), inviteID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to revoke invite")
+		return
+	}
+	if n == 0 {
+		respondWithError(w, http.StatusNotFound, "Invite not found")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, struct{}{})
+}
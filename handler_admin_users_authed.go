@@ -0,0 +1,249 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/JustinLi007/rss-aggregator/internal/database"
+	"github.com/google/uuid"
+)
+
+// The handlers in this file are mounted with the admin scope, which
+// middlewareAuth only grants to users with the admin role.
+
+// handlerListUsersAuthed lists the users of the instance, oldest first.
+//
+// Query parameters:
+//   - q: only users whose name contains q, ignoring case
+//   - role: only users with this role
+//   - limit, offset: page size (50 by default, at most 200) and position
+func (cfg *apiConfig) handlerListUsersAuthed(w http.ResponseWriter, r *http.Request, user database.User) {
+	query := r.URL.Query()
+
+	limit, err := parseLimitQueryValue(r, 50, 200)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	offset := 0
+	if value := query.Get("offset"); value != "" {
+		offset, err = strconv.Atoi(value)
+		if err != nil || offset < 0 {
+			respondWithError(w, http.StatusBadRequest, "Invalid offset")
+			return
+		}
+	}
+
+	// ILIKE wildcards in q are matched literally.
+	search := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(strings.TrimSpace(query.Get("q")))
+	role := query.Get("role")
+
+	users, err := cfg.DB.ListUsers(r.Context(), database.ListUsersParams{
+		Search:    sql.NullString{String: search, Valid: search != ""},
+		Role:      sql.NullString{String: role, Valid: role != ""},
+		RowLimit:  int32(limit),
+		RowOffset: int32(offset),
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to retrieve users")
+		return
+	}
+
+	type payload struct {
+		Users      []User `json:"users"`
+		NextOffset *int   `json:"next_offset"`
+	}
+
+	resp := payload{
+		Users: databaseUsersToUsers(users),
+	}
+	if len(users) == limit {
+		nextOffset := offset + limit
+		resp.NextOffset = &nextOffset
+	}
+
+	respondWithJSON(w, http.StatusOK, resp)
+}
+
+func (cfg *apiConfig) handlerGetUserByIDAuthed(w http.ResponseWriter, r *http.Request, user database.User) {
+	target, ok := cfg.getTargetUser(w, r)
+	if !ok {
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, databaseUserToUser(target))
+}
+
+// handlerGetUserFeedFollowsAuthed shows the feeds a user follows, as they
+// would see them in GET /v1/feed_follows.
+func (cfg *apiConfig) handlerGetUserFeedFollowsAuthed(w http.ResponseWriter, r *http.Request, user database.User) {
+	target, ok := cfg.getTargetUser(w, r)
+	if !ok {
+		return
+	}
+
+	cfg.handlerGetFeedFollowsAuthed(w, r, target)
+}
+
+// handlerDisableUserAuthed locks a user out: their API keys, sessions and
+// output feed tokens stop working until the user is enabled again.
+func (cfg *apiConfig) handlerDisableUserAuthed(w http.ResponseWriter, r *http.Request, user database.User) {
+	target, ok := cfg.getTargetUser(w, r)
+	if !ok {
+		return
+	}
+	if target.ID == user.ID {
+		respondWithError(w, http.StatusBadRequest, "Admins can't disable their own account")
+		return
+	}
+
+	disabled, err := cfg.DB.DisableUser(r.Context(), target.ID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusConflict, "User is already disabled")
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to disable user")
+		return
+	}
+
+	if err := cfg.DB.DeleteOtherSessions(r.Context(), database.DeleteOtherSessionsParams{
+		UserID: target.ID,
+	}); err != nil {
+		log.Printf("Failed to end sessions of user %v: %v", target.ID, err)
+	}
+
+	respondWithJSON(w, http.StatusOK, databaseUserToUser(disabled))
+}
+
+func (cfg *apiConfig) handlerEnableUserAuthed(w http.ResponseWriter, r *http.Request, user database.User) {
+	target, ok := cfg.getTargetUser(w, r)
+	if !ok {
+		return
+	}
+
+	enabled, err := cfg.DB.EnableUser(r.Context(), target.ID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusConflict, "User is not disabled")
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to enable user")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, databaseUserToUser(enabled))
+}
+
+// handlerSetUserRoleAuthed grants or takes away the admin role.
+func (cfg *apiConfig) handlerSetUserRoleAuthed(w http.ResponseWriter, r *http.Request, user database.User) {
+	type parameters struct {
+		Role string `json:"role"`
+	}
+
+	target, ok := cfg.getTargetUser(w, r)
+	if !ok {
+		return
+	}
+
+	params := parameters{}
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to decode parameters")
+		return
+	}
+
+	if params.Role != roleUser && params.Role != roleAdmin {
+		respondWithError(w, http.StatusBadRequest, "role must be \"user\" or \"admin\"")
+		return
+	}
+	if target.ID == user.ID {
+		respondWithError(w, http.StatusBadRequest, "Admins can't change their own role")
+		return
+	}
+
+	if err := cfg.DB.SetUserRole(r.Context(), database.SetUserRoleParams{
+		ID:   target.ID,
+		Role: params.Role,
+	}); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to set role")
+		return
+	}
+
+	target.Role = params.Role
+	respondWithJSON(w, http.StatusOK, databaseUserToUser(target))
+}
+
+// handlerResetUserAPIKeysAuthed revokes every API key of a user and issues
+// a fresh default key, which is returned once.
+func (cfg *apiConfig) handlerResetUserAPIKeysAuthed(w http.ResponseWriter, r *http.Request, user database.User) {
+	target, ok := cfg.getTargetUser(w, r)
+	if !ok {
+		return
+	}
+
+	var apiKey database.ApiKey
+	var key string
+	err := cfg.withTx(r.Context(), func(db *database.Queries) error {
+		if _, err := db.RevokeAllAPIKeys(r.Context(), target.ID); err != nil {
+			return err
+		}
+		var err error
+		apiKey, key, err = createAPIKey(r.Context(), db, target, defaultAPIKeyName, sql.NullTime{}, userScopes)
+		return err
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to reset API keys")
+		return
+	}
+
+	result := databaseAPIKeyToAPIKey(apiKey)
+	result.Key = key
+	respondWithJSON(w, http.StatusOK, result)
+}
+
+// handlerDeleteUserAuthed deletes a user and everything they own. Feeds that
+// others follow are handed over to another follower.
+func (cfg *apiConfig) handlerDeleteUserAuthed(w http.ResponseWriter, r *http.Request, user database.User) {
+	target, ok := cfg.getTargetUser(w, r)
+	if !ok {
+		return
+	}
+	if target.ID == user.ID {
+		respondWithError(w, http.StatusBadRequest, "Admins can't delete their own account")
+		return
+	}
+
+	if _, err := cfg.DB.DeleteUser(r.Context(), target.ID); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to delete user")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, struct{}{})
+}
+
+// getTargetUser loads the user named by the userID path value, responding
+// with an error when there's no such user.
+func (cfg *apiConfig) getTargetUser(w http.ResponseWriter, r *http.Request) (database.User, bool) {
+	userID, err := uuid.Parse(r.PathValue("userID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid user ID")
+		return database.User{}, false
+	}
+
+	user, err := cfg.DB.GetUserByID(r.Context(), userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "User not found")
+			return database.User{}, false
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to retrieve user")
+		return database.User{}, false
+	}
+	return user, true
+}
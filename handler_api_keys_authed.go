@@ -0,0 +1,179 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/JustinLi007/rss-aggregator/internal/auth"
+	"github.com/JustinLi007/rss-aggregator/internal/database"
+	"github.com/google/uuid"
+)
+
+const defaultAPIKeyName = "default"
+
+// handlerCreateAPIKeyAuthed issues a new named API key. expires_at is
+// optional; keys without it stay valid until revoked. scopes defaults to the
+// scopes of the key making the request, and can't exceed them.
+func (cfg *apiConfig) handlerCreateAPIKeyAuthed(w http.ResponseWriter, r *http.Request, user database.User) {
+	type parameters struct {
+		Name      string     `json:"name"`
+		ExpiresAt *time.Time `json:"expires_at"`
+		Scopes    []string   `json:"scopes"`
+	}
+
+	params := parameters{}
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to decode parameters")
+		return
+	}
+
+	name := strings.TrimSpace(params.Name)
+	if name == "" {
+		respondWithError(w, http.StatusBadRequest, "API key name is required")
+		return
+	}
+
+	expiresAt := sql.NullTime{}
+	if params.ExpiresAt != nil {
+		if !params.ExpiresAt.After(time.Now()) {
+			respondWithError(w, http.StatusBadRequest, "expires_at must be in the future")
+			return
+		}
+		expiresAt = sql.NullTime{Time: params.ExpiresAt.UTC(), Valid: true}
+	}
+
+	granted := scopesFromContext(r.Context())
+	scopes := params.Scopes
+	if scopes == nil {
+		scopes = granted
+	}
+	if len(scopes) == 0 {
+		respondWithError(w, http.StatusBadRequest, "API key needs at least one scope")
+		return
+	}
+	if err := validateScopes(user, granted, scopes); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	apiKey, key, err := createAPIKey(r.Context(), cfg.DB, user, name, expiresAt, scopes)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to create API key")
+		return
+	}
+
+	result := databaseAPIKeyToAPIKey(apiKey)
+	result.Key = key
+	respondWithJSON(w, http.StatusOK, result)
+}
+
+func (cfg *apiConfig) handlerGetAPIKeysAuthed(w http.ResponseWriter, r *http.Request, user database.User) {
+	apiKeys, err := cfg.DB.GetAPIKeysByUser(r.Context(), user.ID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to retrieve API keys")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, databaseAPIKeysToAPIKeys(apiKeys))
+}
+
+func (cfg *apiConfig) handlerRevokeAPIKeyAuthed(w http.ResponseWriter, r *http.Request, user database.User) {
+	apiKeyID, err := parseUUIDPathValue(r, "apiKeyID")
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	revoked, err := cfg.DB.RevokeAPIKey(r.Context(), database.RevokeAPIKeyParams{
+		ID:     apiKeyID,
+		UserID: user.ID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to revoke API key")
+		return
+	}
+	if revoked == 0 {
+		respondWithError(w, http.StatusNotFound, "API key not found")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, struct{}{})
+}
+
+// handlerRotateAPIKeyAuthed replaces a key with a new one carrying the same
+// name, expiry and scopes, and revokes the old key. Like creating a key, it
+// can't hand out scopes the requesting credential doesn't hold.
+func (cfg *apiConfig) handlerRotateAPIKeyAuthed(w http.ResponseWriter, r *http.Request, user database.User) {
+	apiKeyID, err := parseUUIDPathValue(r, "apiKeyID")
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	old, err := cfg.DB.GetAPIKeyByID(r.Context(), database.GetAPIKeyByIDParams{
+		ID:     apiKeyID,
+		UserID: user.ID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusNotFound, "API key not found")
+		return
+	}
+	if err := validateScopes(user, scopesFromContext(r.Context()), old.Scopes); err != nil {
+		respondWithError(w, http.StatusForbidden, err.Error())
+		return
+	}
+
+	// Creating the new key and revoking the old one succeed or fail together.
+	var apiKey database.ApiKey
+	var key string
+	err = cfg.withTx(r.Context(), func(db *database.Queries) error {
+		var err error
+		apiKey, key, err = createAPIKey(r.Context(), db, user, old.Name, old.ExpiresAt, old.Scopes)
+		if err != nil {
+			return err
+		}
+		_, err = db.RevokeAPIKey(r.Context(), database.RevokeAPIKeyParams{
+			ID:     old.ID,
+			UserID: user.ID,
+		})
+		return err
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to rotate API key")
+		return
+	}
+
+	result := databaseAPIKeyToAPIKey(apiKey)
+	result.Key = key
+	respondWithJSON(w, http.StatusOK, result)
+}
+
+// createAPIKey generates a key for user and stores its hash. The key itself
+// is returned and never stored.
+func createAPIKey(ctx context.Context, db *database.Queries, user database.User, name string, expiresAt sql.NullTime, scopes []string) (database.ApiKey, string, error) {
+	key, err := auth.GenerateToken()
+	if err != nil {
+		return database.ApiKey{}, "", err
+	}
+
+	apiKey, err := db.CreateAPIKey(ctx, database.CreateAPIKeyParams{
+		ID:        uuid.New(),
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+		Name:      name,
+		KeyHash:   auth.HashToken(key),
+		Prefix:    auth.APIKeyPrefix(key),
+		ExpiresAt: expiresAt,
+		Scopes:    scopes,
+		UserID:    user.ID,
+	})
+	if err != nil {
+		return database.ApiKey{}, "", err
+	}
+
+	return apiKey, key, nil
+}
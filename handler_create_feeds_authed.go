@@ -2,11 +2,11 @@ package main
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
-	"time"
 
 	"github.com/JustinLi007/rss-aggregator/internal/database"
-	"github.com/google/uuid"
+	"github.com/JustinLi007/rss-aggregator/internal/feedurl"
 )
 
 func (cfg *apiConfig) handlerCreateFeedsAuthed(w http.ResponseWriter, r *http.Request, user database.User) {
@@ -22,26 +22,17 @@ func (cfg *apiConfig) handlerCreateFeedsAuthed(w http.ResponseWriter, r *http.Re
 		return
 	}
 
-	feed, err := cfg.DB.CreateFeed(r.Context(), database.CreateFeedParams{
-		ID:        uuid.New(),
-		CreatedAt: time.Now().UTC(),
-		UpdatedAt: time.Now().UTC(),
-		Name:      params.Name,
-		Url:       params.URL,
-		UserID:    user.ID,
-	})
+	feed, _, err := cfg.getOrCreateFeed(r.Context(), user, params.Name, params.URL)
 	if err != nil {
+		if errors.Is(err, feedurl.ErrInvalidURL) {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
 		respondWithError(w, http.StatusInternalServerError, "Failed to create feed")
 		return
 	}
 
-	feedFollow, err := cfg.DB.FollowFeed(r.Context(), database.FollowFeedParams{
-		ID:        uuid.New(),
-		CreatedAt: time.Now().UTC(),
-		UpdatedAt: time.Now().UTC(),
-		UserID:    user.ID,
-		FeedID:    feed.ID,
-	})
+	feedFollow, _, err := cfg.followFeed(r.Context(), user, feed.ID)
 	if err != nil {
 		respondWithError(w, http.StatusInternalServerError, "Failed to follow the feed")
 		return
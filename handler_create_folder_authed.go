@@ -0,0 +1,47 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/JustinLi007/rss-aggregator/internal/database"
+	"github.com/google/uuid"
+)
+
+func (cfg *apiConfig) handlerCreateFolderAuthed(w http.ResponseWriter, r *http.Request, user database.User) {
+	type parameters struct {
+		Name string `json:"name"`
+	}
+
+	params := parameters{}
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to decode parameters")
+		return
+	}
+
+	name := strings.TrimSpace(params.Name)
+	if name == "" {
+		respondWithError(w, http.StatusBadRequest, "Folder name is required")
+		return
+	}
+
+	folder, err := cfg.DB.CreateFolder(r.Context(), database.CreateFolderParams{
+		ID:        uuid.New(),
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+		Name:      name,
+		UserID:    user.ID,
+	})
+	if err != nil {
+		if isDuplicateKeyError(err) {
+			respondWithError(w, http.StatusConflict, "Folder already exists")
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to create folder")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, databaseFolderToFolder(folder))
+}
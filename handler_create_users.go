@@ -1,17 +1,33 @@
 package main
 
 import (
+	"database/sql"
 	"encoding/json"
+	"errors"
 	"net/http"
+	"strings"
 	"time"
+	"unicode/utf8"
 
+	"github.com/JustinLi007/rss-aggregator/internal/auth"
 	"github.com/JustinLi007/rss-aggregator/internal/database"
 	"github.com/google/uuid"
 )
 
+// handlerCreateUsers signs up a new user, subject to the registration mode:
+// open to anyone, invite-only, or closed.
 func (cfg *apiConfig) handlerCreateUsers(w http.ResponseWriter, r *http.Request) {
 	type parameters struct {
 		Name string `json:"name"`
+		// Password is optional; accounts without one can only use API keys.
+		Password string `json:"password"`
+		// InviteCode is required in invite-only mode.
+		InviteCode string `json:"invite_code"`
+	}
+
+	if cfg.RegistrationMode == registrationClosed {
+		respondWithError(w, http.StatusForbidden, "Registration is closed")
+		return
 	}
 
 	params := parameters{}
@@ -21,16 +37,83 @@ func (cfg *apiConfig) handlerCreateUsers(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	user, err := cfg.DB.CreateUser(r.Context(), database.CreateUserParams{
-		ID:        uuid.New(),
-		CreatedAt: time.Now().UTC(),
-		UpdatedAt: time.Now().UTC(),
-		Name:      params.Name,
+	name := strings.TrimSpace(params.Name)
+	if name == "" {
+		respondWithError(w, http.StatusBadRequest, "Name is required")
+		return
+	}
+	if utf8.RuneCountInString(name) > maxUserNameLength {
+		respondWithError(w, http.StatusBadRequest, "Name is too long")
+		return
+	}
+
+	passwordHash := sql.NullString{}
+	if params.Password != "" {
+		hash, err := auth.HashPassword(params.Password)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		passwordHash = sql.NullString{String: hash, Valid: true}
+	}
+
+	// Checked up front to answer most taken names without a transaction; the
+	// unique index on names settles concurrent sign ups.
+	exists, err := cfg.DB.UserNameExists(r.Context(), name)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to create user")
+		return
+	}
+	if exists {
+		respondWithError(w, http.StatusConflict, "Name is already taken")
+		return
+	}
+
+	// The invite, the user and their first API key are created together:
+	// a user without a key would be locked out, and a failed sign up
+	// mustn't use up the invite. Using the invite locks its row, so
+	// concurrent sign ups can't exceed its limit.
+	var user database.User
+	var key string
+	err = cfg.withTx(r.Context(), func(db *database.Queries) error {
+		if cfg.RegistrationMode == registrationInvite {
+			if _, err := db.UseInvite(r.Context(), strings.TrimSpace(params.InviteCode)); err != nil {
+				if errors.Is(err, sql.ErrNoRows) {
+					return errInvalidInvite
+				}
+				return err
+			}
+		}
+
+		var err error
+		user, err = db.CreateUser(r.Context(), database.CreateUserParams{
+			ID:           uuid.New(),
+			CreatedAt:    time.Now().UTC(),
+			UpdatedAt:    time.Now().UTC(),
+			Name:         name,
+			PasswordHash: passwordHash,
+		})
+		if err != nil {
+			return err
+		}
+		_, key, err = createAPIKey(r.Context(), db, user, defaultAPIKeyName, sql.NullTime{}, userScopes)
+		return err
 	})
 	if err != nil {
+		if errors.Is(err, errInvalidInvite) {
+			respondWithError(w, http.StatusForbidden, err.Error())
+			return
+		}
+		if isDuplicateKeyError(err) {
+			respondWithError(w, http.StatusConflict, "Name is already taken")
+			return
+		}
 		respondWithError(w, http.StatusInternalServerError, "Failed to create user")
 		return
 	}
 
-	respondWithJSON(w, http.StatusOK, databaseUserToUser(user))
+	// The key is only ever shown here; the server keeps its hash.
+	result := databaseUserToUser(user)
+	result.ApiKey = key
+	respondWithJSON(w, http.StatusOK, result)
 }
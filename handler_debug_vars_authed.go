@@ -0,0 +1,15 @@
+package main
+
+import (
+	"expvar"
+	"net/http"
+
+	"github.com/JustinLi007/rss-aggregator/internal/database"
+)
+
+// handlerDebugVarsAuthed serves the expvar variables, the post_retention
+// metrics among them. They also expose the command line and memory
+// statistics, so the route is mounted with the admin scope.
+func handlerDebugVarsAuthed(w http.ResponseWriter, r *http.Request, user database.User) {
+	expvar.Handler().ServeHTTP(w, r)
+}
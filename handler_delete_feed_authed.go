@@ -0,0 +1,76 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/JustinLi007/rss-aggregator/internal/database"
+)
+
+// handlerDeleteFeedAuthed deletes a feed on behalf of its owner or an admin.
+//
+// When the owner deletes a feed that other users still follow, the feed is
+// kept for them: ownership moves to the longest-standing other follower and
+// the owner merely stops following it. Otherwise, and always for admins, the
+// feed is deleted together with its follows and unstarred posts; starred
+// posts stay with the users who starred them.
+func (cfg *apiConfig) handlerDeleteFeedAuthed(w http.ResponseWriter, r *http.Request, user database.User) {
+	feedID, err := parseUUIDPathValue(r, "feedID")
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	feed, err := cfg.DB.GetFeedByID(r.Context(), feedID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "Feed not found")
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to retrieve feed")
+		return
+	}
+	if !canManageFeed(r.Context(), user, feed) {
+		respondWithError(w, http.StatusForbidden, "Only the feed owner can delete it")
+		return
+	}
+
+	type payload struct {
+		Deleted bool  `json:"deleted"`
+		Feed    *Feed `json:"feed,omitempty"`
+	}
+
+	if feed.UserID == user.ID {
+		// Transfer and unfollow together, so a failed unfollow doesn't leave
+		// the feed handed over while still followed by its old owner.
+		var transferred database.Feed
+		err := cfg.withTx(r.Context(), func(db *database.Queries) error {
+			var err error
+			transferred, err = db.TransferFeedOwnership(r.Context(), database.TransferFeedOwnershipParams{
+				ID:     feed.ID,
+				UserID: user.ID,
+			})
+			if err != nil {
+				return err
+			}
+			return unfollowFeed(r.Context(), db, user, feed.ID)
+		})
+		if err == nil {
+			result := databaseFeedToFeed(transferred)
+			respondWithJSON(w, http.StatusOK, payload{Deleted: false, Feed: &result})
+			return
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusInternalServerError, "Failed to transfer the feed")
+			return
+		}
+	}
+
+	if _, err := cfg.DB.DeleteFeed(r.Context(), feed.ID); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to delete feed")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, payload{Deleted: true})
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/JustinLi007/rss-aggregator/internal/database"
+)
+
+// handlerDeleteFolderAuthed removes a folder. Follows inside it are kept and
+// moved back to the top level.
+func (cfg *apiConfig) handlerDeleteFolderAuthed(w http.ResponseWriter, r *http.Request, user database.User) {
+	folderID, err := parseUUIDPathValue(r, "folderID")
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	deleted, err := cfg.DB.DeleteFolder(r.Context(), database.DeleteFolderParams{
+		ID:     folderID,
+		UserID: user.ID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to delete folder")
+		return
+	}
+	if deleted == 0 {
+		respondWithError(w, http.StatusNotFound, "Folder not found")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, struct{}{})
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"time"
+
+	"github.com/JustinLi007/rss-aggregator/internal/database"
+	"github.com/JustinLi007/rss-aggregator/internal/opml"
+)
+
+// handlerExportOPMLAuthed returns the user's follows as an OPML 2.0 document,
+// with folders as nested outlines and per-user titles applied.
+func (cfg *apiConfig) handlerExportOPMLAuthed(w http.ResponseWriter, r *http.Request, user database.User) {
+	feedFollows, err := cfg.DB.GetFeedFollowsForExport(r.Context(), user.ID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to retrieve feed follows")
+		return
+	}
+
+	data, err := buildSubscriptionsOPML(user, feedFollows)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to encode OPML")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/x-opml; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="subscriptions.opml"`)
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+func buildSubscriptionsOPML(user database.User, feedFollows []database.GetFeedFollowsForExportRow) ([]byte, error) {
+	doc := opml.New(user.Name+" subscriptions", time.Now())
+	for _, v := range feedFollows {
+		title := v.FeedName
+		if v.Title.Valid {
+			title = v.Title.String
+		}
+
+		doc.AddEntry(opml.Entry{
+			Title:   title,
+			XMLURL:  v.Url,
+			HTMLURL: v.SiteUrl.String,
+			Folder:  v.FolderName.String,
+		})
+	}
+
+	buf := new(bytes.Buffer)
+	if err := doc.Write(buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
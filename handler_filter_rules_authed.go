@@ -0,0 +1,316 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/JustinLi007/rss-aggregator/internal/database"
+	"github.com/JustinLi007/rss-aggregator/internal/rules"
+	"github.com/google/uuid"
+)
+
+type filterRuleParameters struct {
+	Name       *string            `json:"name"`
+	Conditions *[]rules.Condition `json:"conditions"`
+	MatchAll   *bool              `json:"match_all"`
+	Actions    *[]rules.Action    `json:"actions"`
+	Tag        *string            `json:"tag"`
+	Enabled    *bool              `json:"enabled"`
+}
+
+// apply overlays the submitted fields on rule and returns the name and
+// enabled flag to store alongside it.
+func (params filterRuleParameters) apply(rule *rules.Rule, name *string, enabled *bool) {
+	if params.Name != nil {
+		*name = strings.TrimSpace(*params.Name)
+	}
+	if params.Conditions != nil {
+		rule.Conditions = *params.Conditions
+	}
+	if params.MatchAll != nil {
+		rule.MatchAll = *params.MatchAll
+	}
+	if params.Actions != nil {
+		rule.Actions = *params.Actions
+	}
+	if params.Tag != nil {
+		rule.Tag = strings.TrimSpace(*params.Tag)
+	}
+	if params.Enabled != nil {
+		*enabled = *params.Enabled
+	}
+}
+
+func ruleActionsToStrings(actions []rules.Action) []string {
+	result := make([]string, len(actions))
+	for i, v := range actions {
+		result[i] = string(v)
+	}
+	return result
+}
+
+// handlerCreateFilterRuleAuthed stores a rule that is applied to every new
+// post of the feeds the user follows. match_all defaults to true.
+func (cfg *apiConfig) handlerCreateFilterRuleAuthed(w http.ResponseWriter, r *http.Request, user database.User) {
+	params := filterRuleParameters{}
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to decode parameters")
+		return
+	}
+
+	rule := rules.Rule{MatchAll: true}
+	name := ""
+	enabled := true
+	params.apply(&rule, &name, &enabled)
+
+	if name == "" {
+		respondWithError(w, http.StatusBadRequest, "Rule name is required")
+		return
+	}
+	if err := rule.Validate(); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	conditions, err := json.Marshal(rule.Conditions)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to encode conditions")
+		return
+	}
+
+	filterRule, err := cfg.DB.CreateFilterRule(r.Context(), database.CreateFilterRuleParams{
+		ID:         uuid.New(),
+		CreatedAt:  time.Now().UTC(),
+		UpdatedAt:  time.Now().UTC(),
+		Name:       name,
+		Conditions: conditions,
+		MatchAll:   rule.MatchAll,
+		Actions:    ruleActionsToStrings(rule.Actions),
+		Tag:        sql.NullString{String: rule.Tag, Valid: rule.Tag != ""},
+		Enabled:    enabled,
+		UserID:     user.ID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to create filter rule")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, databaseFilterRuleToFilterRule(filterRule))
+}
+
+func (cfg *apiConfig) handlerGetFilterRulesAuthed(w http.ResponseWriter, r *http.Request, user database.User) {
+	filterRules, err := cfg.DB.GetFilterRulesByUser(r.Context(), user.ID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to retrieve filter rules")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, databaseFilterRulesToFilterRules(filterRules))
+}
+
+// handlerUpdateFilterRuleAuthed changes the submitted fields of a rule. The
+// new version only applies to posts scraped from now on.
+func (cfg *apiConfig) handlerUpdateFilterRuleAuthed(w http.ResponseWriter, r *http.Request, user database.User) {
+	filterRuleID, err := parseUUIDPathValue(r, "filterRuleID")
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	params := filterRuleParameters{}
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to decode parameters")
+		return
+	}
+
+	existing, err := cfg.DB.GetFilterRuleByID(r.Context(), database.GetFilterRuleByIDParams{
+		ID:     filterRuleID,
+		UserID: user.ID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "Filter rule not found")
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to retrieve filter rule")
+		return
+	}
+
+	rule, err := databaseFilterRuleToRule(existing)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to decode filter rule")
+		return
+	}
+	name := existing.Name
+	enabled := existing.Enabled
+	params.apply(&rule, &name, &enabled)
+
+	if name == "" {
+		respondWithError(w, http.StatusBadRequest, "Rule name is required")
+		return
+	}
+	if err := rule.Validate(); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	conditions, err := json.Marshal(rule.Conditions)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to encode conditions")
+		return
+	}
+
+	filterRule, err := cfg.DB.UpdateFilterRule(r.Context(), database.UpdateFilterRuleParams{
+		Name:       name,
+		Conditions: conditions,
+		MatchAll:   rule.MatchAll,
+		Actions:    ruleActionsToStrings(rule.Actions),
+		Tag:        sql.NullString{String: rule.Tag, Valid: rule.Tag != ""},
+		Enabled:    enabled,
+		ID:         filterRuleID,
+		UserID:     user.ID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "Filter rule not found")
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to update filter rule")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, databaseFilterRuleToFilterRule(filterRule))
+}
+
+func (cfg *apiConfig) handlerDeleteFilterRuleAuthed(w http.ResponseWriter, r *http.Request, user database.User) {
+	filterRuleID, err := parseUUIDPathValue(r, "filterRuleID")
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	deleted, err := cfg.DB.DeleteFilterRule(r.Context(), database.DeleteFilterRuleParams{
+		ID:     filterRuleID,
+		UserID: user.ID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to delete filter rule")
+		return
+	}
+	if deleted == 0 {
+		respondWithError(w, http.StatusNotFound, "Filter rule not found")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, struct{}{})
+}
+
+// handlerDryRunFilterRuleAuthed lists the recent posts of the followed feeds
+// that the submitted conditions match, without storing or applying anything.
+// limit sets how many recent posts are scanned, 200 by default and at most
+// 1000.
+func (cfg *apiConfig) handlerDryRunFilterRuleAuthed(w http.ResponseWriter, r *http.Request, user database.User) {
+	params := filterRuleParameters{}
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to decode parameters")
+		return
+	}
+
+	limit, err := parseLimitQueryValue(r, 200, 1000)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	rule := rules.Rule{MatchAll: true}
+	name := ""
+	enabled := true
+	params.apply(&rule, &name, &enabled)
+
+	matcher, err := rules.Compile(rule)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	posts, err := cfg.DB.GetRecentPostsForUser(r.Context(), database.GetRecentPostsForUserParams{
+		UserID: user.ID,
+		Limit:  int32(limit),
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to retrieve posts")
+		return
+	}
+
+	matched := []Post{}
+	for _, v := range posts {
+		if matcher.Match(databasePostToRulesPost(v.Post, v.FeedName)) {
+			matched = append(matched, databasePostToPost(v.Post))
+		}
+	}
+
+	respondWithJSON(w, http.StatusOK, matched)
+}
+
+// handlerApplyFilterRuleAuthed runs a stored rule over the recent posts of
+// the followed feeds, as if they had just been scraped. limit works as for
+// the dry run.
+func (cfg *apiConfig) handlerApplyFilterRuleAuthed(w http.ResponseWriter, r *http.Request, user database.User) {
+	filterRuleID, err := parseUUIDPathValue(r, "filterRuleID")
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	limit, err := parseLimitQueryValue(r, 200, 1000)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	filterRule, err := cfg.DB.GetFilterRuleByID(r.Context(), database.GetFilterRuleByIDParams{
+		ID:     filterRuleID,
+		UserID: user.ID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusNotFound, "Filter rule not found")
+		return
+	}
+
+	compiled, err := compileFilterRule(filterRule)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to compile filter rule")
+		return
+	}
+
+	posts, err := cfg.DB.GetRecentPostsForUser(r.Context(), database.GetRecentPostsForUserParams{
+		UserID: user.ID,
+		Limit:  int32(limit),
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to retrieve posts")
+		return
+	}
+
+	type payload struct {
+		Matched int `json:"matched"`
+	}
+
+	result := payload{}
+	for _, v := range posts {
+		if !compiled.matcher.Match(databasePostToRulesPost(v.Post, v.FeedName)) {
+			continue
+		}
+		if err := applyFilterRule(r.Context(), cfg.DB, filterRule, v.Post); err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Failed to apply filter rule")
+			return
+		}
+		result.Matched++
+	}
+
+	respondWithJSON(w, http.StatusOK, result)
+}
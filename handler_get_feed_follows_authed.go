@@ -1,17 +1,44 @@
 package main
 
 import (
+	"context"
 	"net/http"
 
 	"github.com/JustinLi007/rss-aggregator/internal/database"
+	"github.com/google/uuid"
 )
 
 func (cfg *apiConfig) handlerGetFeedFollowsAuthed(w http.ResponseWriter, r *http.Request, user database.User) {
-	feedFollows, err := cfg.DB.GetFeedFollows(r.Context(), user.ID)
+	result, err := cfg.getFeedFollowsWithUnreadCounts(r.Context(), user.ID)
 	if err != nil {
 		respondWithError(w, http.StatusInternalServerError, "Failed to retrieve feed follows")
 		return
 	}
 
-	respondWithJSON(w, http.StatusOK, databaseFeedFollowsToFeedFollows(feedFollows))
+	respondWithJSON(w, http.StatusOK, result)
+}
+
+// getFeedFollowsWithUnreadCounts loads the user's feed follows along with
+// the number of unread posts of each feed.
+func (cfg *apiConfig) getFeedFollowsWithUnreadCounts(ctx context.Context, userID uuid.UUID) ([]UsersFeedsFollow, error) {
+	feedFollows, err := cfg.DB.GetFeedFollows(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	unreadCounts, err := cfg.DB.GetUnreadCountsByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	unreadByFeed := make(map[uuid.UUID]int64, len(unreadCounts))
+	for _, v := range unreadCounts {
+		unreadByFeed[v.FeedID] = v.UnreadCount
+	}
+
+	result := databaseFeedFollowRowsToFeedFollows(feedFollows)
+	for i := range result {
+		result[i].UnreadCount = unreadByFeed[result[i].FeedID]
+	}
+	return result, nil
 }
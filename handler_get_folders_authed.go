@@ -0,0 +1,34 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/JustinLi007/rss-aggregator/internal/database"
+	"github.com/google/uuid"
+)
+
+func (cfg *apiConfig) handlerGetFoldersAuthed(w http.ResponseWriter, r *http.Request, user database.User) {
+	folders, err := cfg.DB.GetFoldersByUser(r.Context(), user.ID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to retrieve folders")
+		return
+	}
+
+	unreadCounts, err := cfg.DB.GetUnreadCountsByFolder(r.Context(), user.ID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to retrieve unread counts")
+		return
+	}
+
+	unreadByFolder := make(map[uuid.UUID]int64, len(unreadCounts))
+	for _, v := range unreadCounts {
+		unreadByFolder[v.FolderID] = v.UnreadCount
+	}
+
+	result := databaseFoldersToFolders(folders)
+	for i := range result {
+		result[i].UnreadCount = unreadByFolder[result[i].ID]
+	}
+
+	respondWithJSON(w, http.StatusOK, result)
+}
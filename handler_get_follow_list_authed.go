@@ -0,0 +1,35 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/JustinLi007/rss-aggregator/internal/database"
+)
+
+// handlerGetFollowListAuthed lists everything a client shows as a feed: the
+// user's feed follows, then their saved searches. kind tells the entries
+// apart; id is the follow's ID for feeds and the saved search's ID for saved
+// searches, to be used with the endpoints of that kind.
+func (cfg *apiConfig) handlerGetFollowListAuthed(w http.ResponseWriter, r *http.Request, user database.User) {
+	feedFollows, err := cfg.getFeedFollowsWithUnreadCounts(r.Context(), user.ID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to retrieve feed follows")
+		return
+	}
+
+	savedSearches, err := cfg.getSavedSearchesWithUnreadCounts(r.Context(), user.ID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to retrieve saved searches")
+		return
+	}
+
+	result := make([]FollowListEntry, 0, len(feedFollows)+len(savedSearches))
+	for _, v := range feedFollows {
+		result = append(result, feedFollowToFollowListEntry(v))
+	}
+	for _, v := range savedSearches {
+		result = append(result, savedSearchToFollowListEntry(v))
+	}
+
+	respondWithJSON(w, http.StatusOK, result)
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"time"
+
+	"github.com/JustinLi007/rss-aggregator/internal/database"
+	"github.com/JustinLi007/rss-aggregator/internal/feedgen"
+)
+
+const (
+	outputFeedFormatRSS  = "rss"
+	outputFeedFormatAtom = "atom"
+	outputFeedFormatJSON = "json"
+)
+
+var outputFeedFormats = []string{outputFeedFormatRSS, outputFeedFormatAtom, outputFeedFormatJSON}
+
+// handlerGetOutputFeed renders the timeline of the token's owner as RSS 2.0,
+// Atom or JSON Feed. Feed readers can't send the Authorization header, so the
+// secret token in the path authenticates the request instead.
+//
+// Query parameters:
+//   - folder_id: render a single folder instead of the whole timeline
+//   - starred: "true" to render the starred posts
+//   - saved_search_id: render the posts matching a saved search
+//   - label_id: render the posts carrying one of the user's labels
+//   - limit: number of items, 50 by default and at most 200
+func (cfg *apiConfig) handlerGetOutputFeed(w http.ResponseWriter, r *http.Request) {
+	format := r.PathValue("format")
+	switch format {
+	case outputFeedFormatRSS, outputFeedFormatAtom, outputFeedFormatJSON:
+	default:
+		respondWithError(w, http.StatusNotFound, "Unknown feed format")
+		return
+	}
+
+	user, err := cfg.DB.GetUserByOutputFeedToken(r.Context(), r.PathValue("token"))
+	if err != nil {
+		respondWithError(w, http.StatusNotFound, "Feed not found")
+		return
+	}
+
+	limit, err := parseLimitQueryValue(r, 50, 200)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	folderID, err := parseNullUUIDQueryValue(r, "folder_id")
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	starred, err := parseBoolQueryValue(r, "starred")
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	savedSearchID, err := parseNullUUIDQueryValue(r, "saved_search_id")
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	labelID, err := parseNullUUIDQueryValue(r, "label_id")
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	title := user.Name + " timeline"
+	var posts []database.Post
+	switch {
+	case starred:
+		title = user.Name + " starred posts"
+		posts, err = cfg.DB.GetStarredPostsForUser(r.Context(), database.GetStarredPostsForUserParams{
+			UserID:   user.ID,
+			RowLimit: int32(limit),
+		})
+	case savedSearchID.Valid:
+		savedSearch, savedSearchErr := cfg.DB.GetSavedSearchByID(r.Context(), database.GetSavedSearchByIDParams{
+			ID:     savedSearchID.UUID,
+			UserID: user.ID,
+		})
+		if savedSearchErr != nil {
+			respondWithError(w, http.StatusNotFound, "Saved search not found")
+			return
+		}
+		title = user.Name + " - " + savedSearch.Name
+		posts, err = cfg.DB.GetPostsForSavedSearch(r.Context(), database.GetPostsForSavedSearchParams{
+			SavedSearchID: savedSearchID.UUID,
+			UserID:        user.ID,
+			RowLimit:      int32(limit),
+		})
+	case labelID.Valid:
+		label, labelErr := cfg.DB.GetLabelByID(r.Context(), database.GetLabelByIDParams{
+			ID:     labelID.UUID,
+			UserID: user.ID,
+		})
+		if labelErr != nil {
+			respondWithError(w, http.StatusNotFound, "Label not found")
+			return
+		}
+		title = user.Name + " - " + label.Name
+		posts, err = cfg.DB.GetPostsForUser(r.Context(), database.GetPostsForUserParams{
+			UserID:   user.ID,
+			LabelID:  labelID,
+			RowLimit: int32(limit),
+		})
+	case folderID.Valid:
+		folder, folderErr := cfg.DB.GetFolderByID(r.Context(), database.GetFolderByIDParams{
+			ID:     folderID.UUID,
+			UserID: user.ID,
+		})
+		if folderErr != nil {
+			respondWithError(w, http.StatusNotFound, "Folder not found")
+			return
+		}
+		title = user.Name + " - " + folder.Name
+		posts, err = cfg.DB.GetPostsForUser(r.Context(), database.GetPostsForUserParams{
+			UserID:   user.ID,
+			FolderID: folderID,
+			RowLimit: int32(limit),
+		})
+	default:
+		posts, err = cfg.DB.GetPostsForUser(r.Context(), database.GetPostsForUserParams{
+			UserID:   user.ID,
+			RowLimit: int32(limit),
+		})
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to retrieve posts")
+		return
+	}
+
+	feed := feedgen.Feed{
+		ID:          "urn:uuid:" + user.ID.String(),
+		Title:       title,
+		Description: "Posts aggregated for " + user.Name,
+		Link:        requestBaseURL(r),
+		FeedURL:     requestBaseURL(r) + r.URL.RequestURI(),
+		Author:      user.Name,
+		Updated:     time.Now().UTC(),
+		Items:       databasePostsToFeedItems(posts),
+	}
+	if len(posts) > 0 {
+		feed.Updated = posts[0].PublishedAt
+	}
+
+	buf := new(bytes.Buffer)
+	contentType := ""
+	switch format {
+	case outputFeedFormatRSS:
+		contentType = "application/rss+xml; charset=utf-8"
+		err = feedgen.WriteRSS(buf, feed)
+	case outputFeedFormatAtom:
+		contentType = "application/atom+xml; charset=utf-8"
+		err = feedgen.WriteAtom(buf, feed)
+	case outputFeedFormatJSON:
+		contentType = "application/feed+json; charset=utf-8"
+		err = feedgen.WriteJSONFeed(buf, feed)
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to render feed")
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(http.StatusOK)
+	w.Write(buf.Bytes())
+}
+
+func databasePostsToFeedItems(posts []database.Post) []feedgen.Item {
+	result := make([]feedgen.Item, len(posts))
+	for i, v := range posts {
+		result[i] = feedgen.Item{
+			ID:          "urn:uuid:" + v.ID.String(),
+			Title:       v.Title,
+			Link:        v.Url,
+			Description: v.Description.String,
+			Published:   v.PublishedAt,
+			Updated:     v.UpdatedAt,
+		}
+	}
+	return result
+}
@@ -1,34 +1,133 @@
 package main
 
 import (
-	"encoding/json"
-	"log"
+	"database/sql"
+	"errors"
 	"net/http"
 
 	"github.com/JustinLi007/rss-aggregator/internal/database"
+	"github.com/google/uuid"
 )
 
+// handlerGetPostsByUser returns the timeline of the feeds the user follows.
+//
+// Query parameters:
+//   - limit: page size, 10 by default and at most 100
+//   - order: "newest" (default) or "oldest"
+//   - before, after: cursors returned as next_cursor; with the default order
+//     pass next_cursor as before, with order=oldest pass it as after
+//   - since, until: RFC 3339 timestamps or YYYY-MM-DD dates bounding
+//     published_at (since is inclusive, until is exclusive)
+//   - feed_id: restrict to one or more feeds, repeated or comma separated
+//   - folder_id: restrict to the feeds filed in one of the user's folders
+//   - label_id: restrict to the posts carrying one of the user's labels
+//   - unread: "true" to only return posts the user has not read
 func (cfg *apiConfig) handlerGetPostsByUser(w http.ResponseWriter, r *http.Request, user database.User) {
-	type parameters struct {
-		Limit int `json:"limit"`
+	query := r.URL.Query()
+
+	limit, err := parseLimitQueryValue(r, 10, 100)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
 	}
 
-	params := parameters{
-		Limit: 10,
+	feedIDs, err := parseUUIDQueryValues(r, "feed_id")
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
 	}
 
-	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
-		log.Printf("Failed to decode parameters")
+	folderID, err := parseNullUUIDQueryValue(r, "folder_id")
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
 	}
 
-	posts, err := cfg.DB.GetPostByUser(r.Context(), database.GetPostByUserParams{
-		UserID: user.ID,
-		Limit:  int32(params.Limit),
-	})
+	labelID, err := parseNullUUIDQueryValue(r, "label_id")
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if labelID.Valid {
+		if _, err := cfg.DB.GetLabelByID(r.Context(), database.GetLabelByIDParams{
+			ID:     labelID.UUID,
+			UserID: user.ID,
+		}); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				respondWithError(w, http.StatusNotFound, "Label not found")
+				return
+			}
+			respondWithError(w, http.StatusInternalServerError, "Failed to retrieve label")
+			return
+		}
+	}
+
+	since, err := parseTimeQueryValue(r, "since")
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	until, err := parseTimeQueryValue(r, "until")
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	unreadOnly, err := parseBoolQueryValue(r, "unread")
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	params := database.GetPostsForUserParams{
+		UserID:     user.ID,
+		FeedIds:    feedIDs,
+		FolderID:   folderID,
+		LabelID:    labelID,
+		Since:      since,
+		Until:      until,
+		UnreadOnly: unreadOnly,
+		RowLimit:   int32(limit),
+	}
+
+	if before := query.Get("before"); before != "" {
+		cursor, err := parsePostCursor(before)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		params.BeforePublishedAt = sql.NullTime{Time: cursor.PublishedAt, Valid: true}
+		params.BeforeID = uuid.NullUUID{UUID: cursor.ID, Valid: true}
+	}
+
+	if after := query.Get("after"); after != "" {
+		cursor, err := parsePostCursor(after)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		params.AfterPublishedAt = sql.NullTime{Time: cursor.PublishedAt, Valid: true}
+		params.AfterID = uuid.NullUUID{UUID: cursor.ID, Valid: true}
+	}
+
+	var posts []database.Post
+	switch query.Get("order") {
+	case "", "newest":
+		posts, err = cfg.DB.GetPostsForUser(r.Context(), params)
+	case "oldest":
+		posts, err = cfg.DB.GetPostsForUserOldestFirst(r.Context(), database.GetPostsForUserOldestFirstParams(params))
+	default:
+		respondWithError(w, http.StatusBadRequest, "Invalid order")
+		return
+	}
 	if err != nil {
 		respondWithError(w, http.StatusInternalServerError, "Failed to retrieve posts")
 		return
 	}
 
-	respondWithJSON(w, http.StatusOK, databasePostsToPosts(posts))
+	respondWithJSON(w, http.StatusOK, PostsPage{
+		Posts:      databasePostsToPosts(posts),
+		NextCursor: nextPostCursor(posts, limit),
+	})
 }
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/JustinLi007/rss-aggregator/internal/database"
+	"github.com/google/uuid"
+)
+
+// handlerGetStarredPostsAuthed lists the user's starred posts, newest first.
+// Starred posts stay listed after the user unfollows their feed or the feed
+// is deleted. Supports the limit and before parameters of GET /v1/posts.
+func (cfg *apiConfig) handlerGetStarredPostsAuthed(w http.ResponseWriter, r *http.Request, user database.User) {
+	limit, err := parseLimitQueryValue(r, 10, 100)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	params := database.GetStarredPostsForUserParams{
+		UserID:   user.ID,
+		RowLimit: int32(limit),
+	}
+
+	if before := r.URL.Query().Get("before"); before != "" {
+		cursor, err := parsePostCursor(before)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		params.BeforePublishedAt = sql.NullTime{Time: cursor.PublishedAt, Valid: true}
+		params.BeforeID = uuid.NullUUID{UUID: cursor.ID, Valid: true}
+	}
+
+	posts, err := cfg.DB.GetStarredPostsForUser(r.Context(), params)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to retrieve starred posts")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, PostsPage{
+		Posts:      databasePostsToPosts(posts),
+		NextCursor: nextPostCursor(posts, limit),
+	})
+}
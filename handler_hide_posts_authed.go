@@ -0,0 +1,58 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/JustinLi007/rss-aggregator/internal/database"
+	"github.com/google/uuid"
+)
+
+// handlerHidePostAuthed removes a post from the user's timeline, as the hide
+// action of a filter rule does. Only posts of followed feeds can be hidden.
+func (cfg *apiConfig) handlerHidePostAuthed(w http.ResponseWriter, r *http.Request, user database.User) {
+	postID, err := parseUUIDPathValue(r, "postID")
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	followed, err := cfg.postsFollowed(r.Context(), user.ID, []uuid.UUID{postID})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to hide post")
+		return
+	}
+	if !followed {
+		respondWithError(w, http.StatusNotFound, "Post not found")
+		return
+	}
+
+	updated, err := cfg.DB.HidePost(r.Context(), database.HidePostParams{
+		UserID: user.ID,
+		PostID: postID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to hide post")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, markPostsPayload{Updated: updated})
+}
+
+func (cfg *apiConfig) handlerUnhidePostAuthed(w http.ResponseWriter, r *http.Request, user database.User) {
+	postID, err := parseUUIDPathValue(r, "postID")
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	updated, err := cfg.DB.UnhidePost(r.Context(), database.UnhidePostParams{
+		UserID: user.ID,
+		PostID: postID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to unhide post")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, markPostsPayload{Updated: updated})
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/JustinLi007/rss-aggregator/internal/database"
+	"github.com/JustinLi007/rss-aggregator/internal/feedurl"
+	"github.com/JustinLi007/rss-aggregator/internal/opml"
+	"github.com/google/uuid"
+)
+
+const maxOPMLSize = 5 << 20
+
+const (
+	opmlImportCreated         = "created"
+	opmlImportFollowed        = "followed"
+	opmlImportAlreadyFollowed = "already_followed"
+	opmlImportInvalidURL      = "invalid_url"
+	opmlImportFailed          = "failed"
+)
+
+type opmlImportEntry struct {
+	Title        string     `json:"title"`
+	URL          string     `json:"url"`
+	Folder       string     `json:"folder,omitempty"`
+	Status       string     `json:"status"`
+	FeedID       *uuid.UUID `json:"feed_id,omitempty"`
+	FeedFollowID *uuid.UUID `json:"feed_follow_id,omitempty"`
+}
+
+// handlerImportOPMLAuthed subscribes the user to every feed listed in the
+// OPML document sent as the request body. Missing feeds are created, existing
+// ones are followed and nested outlines become folders. The response reports
+// what happened to each entry.
+func (cfg *apiConfig) handlerImportOPMLAuthed(w http.ResponseWriter, r *http.Request, user database.User) {
+	doc, err := opml.Parse(http.MaxBytesReader(w, r.Body, maxOPMLSize))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	folders := map[string]database.Folder{}
+	entries := doc.Entries()
+	report := make([]opmlImportEntry, len(entries))
+
+	for i, v := range entries {
+		report[i] = opmlImportEntry{
+			Title:  v.Title,
+			URL:    v.XMLURL,
+			Folder: v.Folder,
+		}
+
+		name := v.Title
+		if name == "" {
+			name = v.XMLURL
+		}
+
+		feed, created, err := cfg.getOrCreateFeed(r.Context(), user, name, v.XMLURL)
+		if err != nil {
+			report[i].Status = opmlImportFailed
+			if errors.Is(err, feedurl.ErrInvalidURL) {
+				report[i].Status = opmlImportInvalidURL
+			}
+			continue
+		}
+		report[i].FeedID = &feed.ID
+
+		if created && v.HTMLURL != "" {
+			if err := cfg.DB.SetFeedSiteURL(r.Context(), database.SetFeedSiteURLParams{
+				ID:      feed.ID,
+				SiteUrl: sql.NullString{String: v.HTMLURL, Valid: true},
+			}); err != nil {
+				log.Printf("Failed to save site url of feed %v: %v", feed.Name, err)
+			}
+		}
+
+		feedFollow, followed, err := cfg.followFeed(r.Context(), user, feed.ID)
+		if err != nil {
+			report[i].Status = opmlImportFailed
+			continue
+		}
+		report[i].FeedFollowID = &feedFollow.ID
+
+		switch {
+		case created:
+			report[i].Status = opmlImportCreated
+		case followed:
+			report[i].Status = opmlImportFollowed
+		default:
+			report[i].Status = opmlImportAlreadyFollowed
+			continue
+		}
+
+		if v.Folder == "" {
+			continue
+		}
+
+		folder, ok := folders[v.Folder]
+		if !ok {
+			folder, err = cfg.getOrCreateFolder(r.Context(), user, v.Folder)
+			if err != nil {
+				log.Printf("Failed to create folder %v: %v", v.Folder, err)
+				continue
+			}
+			folders[v.Folder] = folder
+		}
+
+		if _, err := cfg.DB.SetFeedFollowFolder(r.Context(), database.SetFeedFollowFolderParams{
+			FolderID: uuid.NullUUID{UUID: folder.ID, Valid: true},
+			ID:       feedFollow.ID,
+			UserID:   user.ID,
+		}); err != nil {
+			log.Printf("Failed to file feed follow %v into folder %v: %v", feedFollow.ID, folder.Name, err)
+		}
+	}
+
+	type payload struct {
+		Entries []opmlImportEntry `json:"entries"`
+	}
+
+	respondWithJSON(w, http.StatusOK, payload{
+		Entries: report,
+	})
+}
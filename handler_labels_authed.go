@@ -0,0 +1,205 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/JustinLi007/rss-aggregator/internal/database"
+	"github.com/google/uuid"
+)
+
+func (cfg *apiConfig) handlerCreateLabelAuthed(w http.ResponseWriter, r *http.Request, user database.User) {
+	type parameters struct {
+		Name string `json:"name"`
+	}
+
+	params := parameters{}
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to decode parameters")
+		return
+	}
+
+	name := strings.TrimSpace(params.Name)
+	if name == "" {
+		respondWithError(w, http.StatusBadRequest, "Label name is required")
+		return
+	}
+
+	label, err := cfg.DB.CreateLabel(r.Context(), database.CreateLabelParams{
+		ID:        uuid.New(),
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+		Name:      name,
+		UserID:    user.ID,
+	})
+	if err != nil {
+		if isDuplicateKeyError(err) {
+			respondWithError(w, http.StatusConflict, "Label already exists")
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to create label")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, databaseLabelToLabel(label))
+}
+
+// handlerGetLabelsAuthed lists the user's labels with the number of posts
+// carrying each of them and how many of those are unread.
+func (cfg *apiConfig) handlerGetLabelsAuthed(w http.ResponseWriter, r *http.Request, user database.User) {
+	labels, err := cfg.DB.GetLabelsByUser(r.Context(), user.ID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to retrieve labels")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, databaseLabelRowsToLabels(labels))
+}
+
+func (cfg *apiConfig) handlerUpdateLabelAuthed(w http.ResponseWriter, r *http.Request, user database.User) {
+	labelID, err := parseUUIDPathValue(r, "labelID")
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	type parameters struct {
+		Name string `json:"name"`
+	}
+
+	params := parameters{}
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to decode parameters")
+		return
+	}
+
+	name := strings.TrimSpace(params.Name)
+	if name == "" {
+		respondWithError(w, http.StatusBadRequest, "Label name is required")
+		return
+	}
+
+	label, err := cfg.DB.UpdateLabel(r.Context(), database.UpdateLabelParams{
+		Name:   name,
+		ID:     labelID,
+		UserID: user.ID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "Label not found")
+			return
+		}
+		if isDuplicateKeyError(err) {
+			respondWithError(w, http.StatusConflict, "Label already exists")
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to update label")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, databaseLabelToLabel(label))
+}
+
+// handlerDeleteLabelAuthed removes a label from every post carrying it and
+// deletes it. The posts themselves are kept.
+func (cfg *apiConfig) handlerDeleteLabelAuthed(w http.ResponseWriter, r *http.Request, user database.User) {
+	labelID, err := parseUUIDPathValue(r, "labelID")
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	deleted, err := cfg.DB.DeleteLabel(r.Context(), database.DeleteLabelParams{
+		ID:     labelID,
+		UserID: user.ID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to delete label")
+		return
+	}
+	if deleted == 0 {
+		respondWithError(w, http.StatusNotFound, "Label not found")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, struct{}{})
+}
+
+// handlerAddPostLabelAuthed labels a post of a followed feed.
+func (cfg *apiConfig) handlerAddPostLabelAuthed(w http.ResponseWriter, r *http.Request, user database.User) {
+	postID, labelID, ok := cfg.parsePostLabelPath(w, r, user)
+	if !ok {
+		return
+	}
+
+	followed, err := cfg.postsFollowed(r.Context(), user.ID, []uuid.UUID{postID})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to label post")
+		return
+	}
+	if !followed {
+		respondWithError(w, http.StatusNotFound, "Post not found")
+		return
+	}
+
+	updated, err := cfg.DB.AddPostLabel(r.Context(), database.AddPostLabelParams{
+		LabelID:   labelID,
+		PostID:    postID,
+		CreatedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to label post")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, markPostsPayload{Updated: updated})
+}
+
+func (cfg *apiConfig) handlerRemovePostLabelAuthed(w http.ResponseWriter, r *http.Request, user database.User) {
+	postID, labelID, ok := cfg.parsePostLabelPath(w, r, user)
+	if !ok {
+		return
+	}
+
+	updated, err := cfg.DB.RemovePostLabel(r.Context(), database.RemovePostLabelParams{
+		LabelID: labelID,
+		PostID:  postID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to unlabel post")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, markPostsPayload{Updated: updated})
+}
+
+// parsePostLabelPath reads the post and label IDs of
+// /v1/posts/{postID}/labels/{labelID} and checks that the label belongs to
+// the user. It writes the error response itself when it returns false.
+func (cfg *apiConfig) parsePostLabelPath(w http.ResponseWriter, r *http.Request, user database.User) (uuid.UUID, uuid.UUID, bool) {
+	postID, err := parseUUIDPathValue(r, "postID")
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	labelID, err := parseUUIDPathValue(r, "labelID")
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	if _, err := cfg.DB.GetLabelByID(r.Context(), database.GetLabelByIDParams{
+		ID:     labelID,
+		UserID: user.ID,
+	}); err != nil {
+		respondWithError(w, http.StatusNotFound, "Label not found")
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return postID, labelID, true
+}
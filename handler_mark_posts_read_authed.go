@@ -0,0 +1,183 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/JustinLi007/rss-aggregator/internal/database"
+	"github.com/google/uuid"
+)
+
+type markPostsPayload struct {
+	Updated int64 `json:"updated"`
+}
+
+func (cfg *apiConfig) handlerMarkPostReadAuthed(w http.ResponseWriter, r *http.Request, user database.User) {
+	postID, err := parseUUIDPathValue(r, "postID")
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	followed, err := cfg.postsFollowed(r.Context(), user.ID, []uuid.UUID{postID})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to mark post as read")
+		return
+	}
+	if !followed {
+		respondWithError(w, http.StatusNotFound, "Post not found")
+		return
+	}
+
+	updated, err := cfg.DB.MarkPostsRead(r.Context(), database.MarkPostsReadParams{
+		UserID:  user.ID,
+		PostIds: []uuid.UUID{postID},
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to mark post as read")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, markPostsPayload{Updated: updated})
+}
+
+func (cfg *apiConfig) handlerMarkPostUnreadAuthed(w http.ResponseWriter, r *http.Request, user database.User) {
+	postID, err := parseUUIDPathValue(r, "postID")
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	updated, err := cfg.DB.MarkPostsUnread(r.Context(), database.MarkPostsUnreadParams{
+		UserID:  user.ID,
+		PostIds: []uuid.UUID{postID},
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to mark post as unread")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, markPostsPayload{Updated: updated})
+}
+
+func (cfg *apiConfig) handlerMarkPostsReadAuthed(w http.ResponseWriter, r *http.Request, user database.User) {
+	type parameters struct {
+		PostIDs []uuid.UUID `json:"post_ids"`
+	}
+
+	params := parameters{}
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to decode parameters")
+		return
+	}
+
+	followed, err := cfg.postsFollowed(r.Context(), user.ID, params.PostIDs)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to mark posts as read")
+		return
+	}
+	if !followed {
+		respondWithError(w, http.StatusNotFound, "Post not found")
+		return
+	}
+
+	updated, err := cfg.DB.MarkPostsRead(r.Context(), database.MarkPostsReadParams{
+		UserID:  user.ID,
+		PostIds: params.PostIDs,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to mark posts as read")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, markPostsPayload{Updated: updated})
+}
+
+func (cfg *apiConfig) handlerMarkPostsUnreadAuthed(w http.ResponseWriter, r *http.Request, user database.User) {
+	type parameters struct {
+		PostIDs []uuid.UUID `json:"post_ids"`
+	}
+
+	params := parameters{}
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to decode parameters")
+		return
+	}
+
+	updated, err := cfg.DB.MarkPostsUnread(r.Context(), database.MarkPostsUnreadParams{
+		UserID:  user.ID,
+		PostIds: params.PostIDs,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to mark posts as unread")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, markPostsPayload{Updated: updated})
+}
+
+// handlerMarkAllPostsReadAuthed marks every post published before the given
+// timestamp as read, either for a single followed feed, a folder or for all
+// of them.
+func (cfg *apiConfig) handlerMarkAllPostsReadAuthed(w http.ResponseWriter, r *http.Request, user database.User) {
+	type parameters struct {
+		Before   *time.Time `json:"before"`
+		FeedID   *uuid.UUID `json:"feed_id"`
+		FolderID *uuid.UUID `json:"folder_id"`
+	}
+
+	params := parameters{}
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to decode parameters")
+		return
+	}
+
+	before := time.Now().UTC()
+	if params.Before != nil {
+		before = params.Before.UTC()
+	}
+
+	feedID := uuid.NullUUID{}
+	if params.FeedID != nil {
+		feedID = uuid.NullUUID{UUID: *params.FeedID, Valid: true}
+	}
+
+	folderID := uuid.NullUUID{}
+	if params.FolderID != nil {
+		folderID = uuid.NullUUID{UUID: *params.FolderID, Valid: true}
+	}
+
+	updated, err := cfg.DB.MarkAllPostsReadBefore(r.Context(), database.MarkAllPostsReadBeforeParams{
+		UserID:   user.ID,
+		Before:   before,
+		FeedID:   feedID,
+		FolderID: folderID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to mark posts as read")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, markPostsPayload{Updated: updated})
+}
+
+// postsFollowed reports whether every post in postIDs belongs to a feed the
+// user follows. Post state can only be set on those.
+func (cfg *apiConfig) postsFollowed(ctx context.Context, userID uuid.UUID, postIDs []uuid.UUID) (bool, error) {
+	unique := slices.Clone(postIDs)
+	slices.SortFunc(unique, func(a, b uuid.UUID) int { return bytes.Compare(a[:], b[:]) })
+	unique = slices.Compact(unique)
+
+	count, err := cfg.DB.CountFollowedPosts(ctx, database.CountFollowedPostsParams{
+		UserID:  userID,
+		PostIds: unique,
+	})
+	if err != nil {
+		return false, err
+	}
+	return int(count) == len(unique), nil
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/JustinLi007/rss-aggregator/internal/database"
+)
+
+// handlerGetNotificationsAuthed lists the posts the user is notified about,
+// newest first: those matched by their notify rules, which carry the rule's
+// ID, and every post of follows with notifications set to "all". Follows
+// that are muted or set to "none" never notify.
+func (cfg *apiConfig) handlerGetNotificationsAuthed(w http.ResponseWriter, r *http.Request, user database.User) {
+	limit, err := parseLimitQueryValue(r, 20, 100)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	notifications, err := cfg.DB.GetNotificationsByUser(r.Context(), database.GetNotificationsByUserParams{
+		UserID: user.ID,
+		Limit:  int32(limit),
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to retrieve notifications")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, databaseNotificationRowsToNotifications(notifications))
+}
+
+func (cfg *apiConfig) handlerDeleteNotificationAuthed(w http.ResponseWriter, r *http.Request, user database.User) {
+	notificationID, err := parseUUIDPathValue(r, "notificationID")
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	deleted, err := cfg.DB.DeleteNotification(r.Context(), database.DeleteNotificationParams{
+		ID:     notificationID,
+		UserID: user.ID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to delete notification")
+		return
+	}
+	if deleted == 0 {
+		respondWithError(w, http.StatusNotFound, "Notification not found")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, struct{}{})
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/JustinLi007/rss-aggregator/internal/auth"
+	"github.com/JustinLi007/rss-aggregator/internal/database"
+	"github.com/google/uuid"
+)
+
+func (cfg *apiConfig) handlerCreateOutputFeedTokenAuthed(w http.ResponseWriter, r *http.Request, user database.User) {
+	type parameters struct {
+		Name string `json:"name"`
+	}
+
+	params := parameters{}
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to decode parameters")
+		return
+	}
+
+	token, err := auth.GenerateToken()
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to generate token")
+		return
+	}
+
+	outputFeedToken, err := cfg.DB.CreateOutputFeedToken(r.Context(), database.CreateOutputFeedTokenParams{
+		ID:        uuid.New(),
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+		Name:      strings.TrimSpace(params.Name),
+		Token:     token,
+		UserID:    user.ID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to create output feed token")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, databaseOutputFeedTokenToOutputFeedToken(outputFeedToken, requestBaseURL(r)))
+}
+
+func (cfg *apiConfig) handlerGetOutputFeedTokensAuthed(w http.ResponseWriter, r *http.Request, user database.User) {
+	tokens, err := cfg.DB.GetOutputFeedTokensByUser(r.Context(), user.ID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to retrieve output feed tokens")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, databaseOutputFeedTokensToOutputFeedTokens(tokens, requestBaseURL(r)))
+}
+
+func (cfg *apiConfig) handlerRevokeOutputFeedTokenAuthed(w http.ResponseWriter, r *http.Request, user database.User) {
+	tokenID, err := parseUUIDPathValue(r, "tokenID")
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	revoked, err := cfg.DB.RevokeOutputFeedToken(r.Context(), database.RevokeOutputFeedTokenParams{
+		ID:     tokenID,
+		UserID: user.ID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to revoke output feed token")
+		return
+	}
+	if revoked == 0 {
+		respondWithError(w, http.StatusNotFound, "Output feed token not found")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, struct{}{})
+}
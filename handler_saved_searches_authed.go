@@ -0,0 +1,242 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/JustinLi007/rss-aggregator/internal/database"
+	"github.com/JustinLi007/rss-aggregator/internal/search"
+	"github.com/google/uuid"
+)
+
+// handlerCreateSavedSearchAuthed pins a search query. Posts already matching
+// it are collected right away; new posts are matched as they are scraped.
+func (cfg *apiConfig) handlerCreateSavedSearchAuthed(w http.ResponseWriter, r *http.Request, user database.User) {
+	type parameters struct {
+		Name  string `json:"name"`
+		Query string `json:"query"`
+	}
+
+	params := parameters{}
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to decode parameters")
+		return
+	}
+
+	tsQuery, err := search.ToTSQuery(params.Query)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	name := strings.TrimSpace(params.Name)
+	if name == "" {
+		name = strings.TrimSpace(params.Query)
+	}
+
+	savedSearch, err := cfg.DB.CreateSavedSearch(r.Context(), database.CreateSavedSearchParams{
+		ID:        uuid.New(),
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+		Name:      name,
+		Query:     strings.TrimSpace(params.Query),
+		TsQuery:   tsQuery,
+		UserID:    user.ID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to create saved search")
+		return
+	}
+
+	matched, err := cfg.DB.MatchPostsForSavedSearch(r.Context(), savedSearch.ID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to evaluate saved search")
+		return
+	}
+
+	result := databaseSavedSearchToSavedSearch(savedSearch)
+	result.UnreadCount = matched
+	respondWithJSON(w, http.StatusOK, result)
+}
+
+// handlerGetSavedSearchesAuthed lists the user's saved searches with the
+// number of unread posts matching each of them.
+func (cfg *apiConfig) handlerGetSavedSearchesAuthed(w http.ResponseWriter, r *http.Request, user database.User) {
+	result, err := cfg.getSavedSearchesWithUnreadCounts(r.Context(), user.ID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to retrieve saved searches")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, result)
+}
+
+// getSavedSearchesWithUnreadCounts loads the user's saved searches along
+// with the number of unread posts from followed feeds matching each.
+func (cfg *apiConfig) getSavedSearchesWithUnreadCounts(ctx context.Context, userID uuid.UUID) ([]SavedSearch, error) {
+	savedSearches, err := cfg.DB.GetSavedSearchesByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	unreadCounts, err := cfg.DB.GetUnreadCountsBySavedSearch(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	unreadBySavedSearch := make(map[uuid.UUID]int64, len(unreadCounts))
+	for _, v := range unreadCounts {
+		unreadBySavedSearch[v.SavedSearchID] = v.UnreadCount
+	}
+
+	result := databaseSavedSearchesToSavedSearches(savedSearches)
+	for i := range result {
+		result[i].UnreadCount = unreadBySavedSearch[result[i].ID]
+	}
+	return result, nil
+}
+
+// handlerUpdateSavedSearchAuthed renames a saved search or changes its
+// query. Changing the query re-evaluates it against the followed posts.
+func (cfg *apiConfig) handlerUpdateSavedSearchAuthed(w http.ResponseWriter, r *http.Request, user database.User) {
+	savedSearchID, err := parseUUIDPathValue(r, "savedSearchID")
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	type parameters struct {
+		Name  *string `json:"name"`
+		Query *string `json:"query"`
+	}
+
+	params := parameters{}
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to decode parameters")
+		return
+	}
+
+	updateParams := database.UpdateSavedSearchParams{
+		ID:     savedSearchID,
+		UserID: user.ID,
+	}
+	if params.Name != nil {
+		name := strings.TrimSpace(*params.Name)
+		if name == "" {
+			respondWithError(w, http.StatusBadRequest, "Saved search name is required")
+			return
+		}
+		updateParams.Name = sql.NullString{String: name, Valid: true}
+	}
+	if params.Query != nil {
+		tsQuery, err := search.ToTSQuery(*params.Query)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		updateParams.Query = sql.NullString{String: strings.TrimSpace(*params.Query), Valid: true}
+		updateParams.TsQuery = sql.NullString{String: tsQuery, Valid: true}
+	}
+
+	savedSearch, err := cfg.DB.UpdateSavedSearch(r.Context(), updateParams)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "Saved search not found")
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to update saved search")
+		return
+	}
+
+	if params.Query != nil {
+		if err := cfg.DB.DeleteSavedSearchMatches(r.Context(), savedSearch.ID); err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Failed to evaluate saved search")
+			return
+		}
+		if _, err := cfg.DB.MatchPostsForSavedSearch(r.Context(), savedSearch.ID); err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Failed to evaluate saved search")
+			return
+		}
+	}
+
+	respondWithJSON(w, http.StatusOK, databaseSavedSearchToSavedSearch(savedSearch))
+}
+
+func (cfg *apiConfig) handlerDeleteSavedSearchAuthed(w http.ResponseWriter, r *http.Request, user database.User) {
+	savedSearchID, err := parseUUIDPathValue(r, "savedSearchID")
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	deleted, err := cfg.DB.DeleteSavedSearch(r.Context(), database.DeleteSavedSearchParams{
+		ID:     savedSearchID,
+		UserID: user.ID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to delete saved search")
+		return
+	}
+	if deleted == 0 {
+		respondWithError(w, http.StatusNotFound, "Saved search not found")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, struct{}{})
+}
+
+// handlerGetSavedSearchPostsAuthed lists the posts matching a saved search,
+// newest first. Supports the limit and before parameters of GET /v1/posts.
+func (cfg *apiConfig) handlerGetSavedSearchPostsAuthed(w http.ResponseWriter, r *http.Request, user database.User) {
+	savedSearchID, err := parseUUIDPathValue(r, "savedSearchID")
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if _, err := cfg.DB.GetSavedSearchByID(r.Context(), database.GetSavedSearchByIDParams{
+		ID:     savedSearchID,
+		UserID: user.ID,
+	}); err != nil {
+		respondWithError(w, http.StatusNotFound, "Saved search not found")
+		return
+	}
+
+	limit, err := parseLimitQueryValue(r, 10, 100)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	params := database.GetPostsForSavedSearchParams{
+		SavedSearchID: savedSearchID,
+		UserID:        user.ID,
+		RowLimit:      int32(limit),
+	}
+
+	if before := r.URL.Query().Get("before"); before != "" {
+		cursor, err := parsePostCursor(before)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		params.BeforePublishedAt = sql.NullTime{Time: cursor.PublishedAt, Valid: true}
+		params.BeforeID = uuid.NullUUID{UUID: cursor.ID, Valid: true}
+	}
+
+	posts, err := cfg.DB.GetPostsForSavedSearch(r.Context(), params)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to retrieve posts")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, PostsPage{
+		Posts:      databasePostsToPosts(posts),
+		NextCursor: nextPostCursor(posts, limit),
+	})
+}
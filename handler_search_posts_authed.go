@@ -0,0 +1,119 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/JustinLi007/rss-aggregator/internal/database"
+	"github.com/JustinLi007/rss-aggregator/internal/search"
+)
+
+// handlerSearchPostsAuthed runs a full-text search over the posts of the
+// feeds the user follows, best matches first. Like the timeline, it leaves
+// out hidden posts, and posts of follows hidden from the timeline unless
+// filtering by feed or folder.
+//
+// Query parameters:
+//   - q: the search terms; see search.ToTSQuery for the supported syntax
+//   - limit, offset: page size (20 by default, at most 100) and position
+//   - feed_id, folder_id, since, until: same as GET /v1/posts
+//   - read, starred: "true" or "false" to filter on the user's post state
+func (cfg *apiConfig) handlerSearchPostsAuthed(w http.ResponseWriter, r *http.Request, user database.User) {
+	query := r.URL.Query()
+
+	tsQuery, err := search.ToTSQuery(query.Get("q"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	limit, err := parseLimitQueryValue(r, 20, 100)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	offset := 0
+	if value := query.Get("offset"); value != "" {
+		offset, err = strconv.Atoi(value)
+		if err != nil || offset < 0 {
+			respondWithError(w, http.StatusBadRequest, "Invalid offset")
+			return
+		}
+	}
+
+	feedIDs, err := parseUUIDQueryValues(r, "feed_id")
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	folderID, err := parseNullUUIDQueryValue(r, "folder_id")
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	since, err := parseTimeQueryValue(r, "since")
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	until, err := parseTimeQueryValue(r, "until")
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	isRead, err := parseNullBoolQueryValue(r, "read")
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	isStarred, err := parseNullBoolQueryValue(r, "starred")
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	results, err := cfg.DB.SearchPostsForUser(r.Context(), database.SearchPostsForUserParams{
+		SearchQuery: tsQuery,
+		UserID:      user.ID,
+		FeedIds:     feedIDs,
+		FolderID:    folderID,
+		Since:       since,
+		Until:       until,
+		IsRead:      isRead,
+		IsStarred:   isStarred,
+		RowLimit:    int32(limit),
+		RowOffset:   int32(offset),
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to search posts")
+		return
+	}
+
+	type payload struct {
+		Results    []SearchResult `json:"results"`
+		NextOffset *int           `json:"next_offset"`
+	}
+
+	resp := payload{
+		Results: make([]SearchResult, len(results)),
+	}
+	for i, v := range results {
+		resp.Results[i] = SearchResult{
+			Post:    databasePostToPost(v.Post),
+			Rank:    v.Rank,
+			Snippet: v.Snippet,
+		}
+	}
+	if len(results) == limit {
+		nextOffset := offset + limit
+		resp.NextOffset = &nextOffset
+	}
+
+	respondWithJSON(w, http.StatusOK, resp)
+}
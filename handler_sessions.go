@@ -0,0 +1,173 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/JustinLi007/rss-aggregator/internal/auth"
+	"github.com/JustinLi007/rss-aggregator/internal/database"
+	"github.com/google/uuid"
+)
+
+// handlerLogin checks a name and password and starts a browser session. The
+// response carries the CSRF token that mutating requests must send back in
+// the X-CSRF-Token header.
+func (cfg *apiConfig) handlerLogin(w http.ResponseWriter, r *http.Request) {
+	type parameters struct {
+		Name     string `json:"name"`
+		Password string `json:"password"`
+	}
+	type response struct {
+		User      User   `json:"user"`
+		CsrfToken string `json:"csrf_token"`
+	}
+
+	params := parameters{}
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to decode parameters")
+		return
+	}
+
+	user, err := cfg.DB.GetUserByName(r.Context(), params.Name)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		respondWithError(w, http.StatusInternalServerError, "Failed to retrieve user")
+		return
+	}
+	if err := auth.CheckPassword(user.PasswordHash.String, params.Password); err != nil {
+		respondWithError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+	if user.DisabledAt.Valid {
+		respondWithError(w, http.StatusForbidden, "Account is disabled")
+		return
+	}
+
+	csrfToken, err := cfg.startSession(w, r, user)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to create session")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, response{
+		User:      databaseUserToUser(user),
+		CsrfToken: csrfToken,
+	})
+}
+
+// handlerLogoutAuthed ends the session the request was made with.
+func (cfg *apiConfig) handlerLogoutAuthed(w http.ResponseWriter, r *http.Request, user database.User) {
+	sessionID, ok := sessionIDFromContext(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusBadRequest, "Request isn't authenticated with a session")
+		return
+	}
+
+	if _, err := cfg.DB.DeleteSession(r.Context(), database.DeleteSessionParams{
+		ID:     sessionID,
+		UserID: user.ID,
+	}); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to log out")
+		return
+	}
+
+	clearSessionCookies(w, r)
+	respondWithJSON(w, http.StatusOK, struct{}{})
+}
+
+func (cfg *apiConfig) handlerGetSessionsAuthed(w http.ResponseWriter, r *http.Request, user database.User) {
+	sessions, err := cfg.DB.GetSessionsByUser(r.Context(), user.ID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to retrieve sessions")
+		return
+	}
+
+	currentID, _ := sessionIDFromContext(r.Context())
+	respondWithJSON(w, http.StatusOK, databaseSessionsToSessions(sessions, currentID))
+}
+
+func (cfg *apiConfig) handlerRevokeSessionAuthed(w http.ResponseWriter, r *http.Request, user database.User) {
+	sessionID, err := uuid.Parse(r.PathValue("sessionID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid session ID")
+		return
+	}
+
+	n, err := cfg.DB.DeleteSession(r.Context(), database.DeleteSessionParams{
+		ID:     sessionID,
+		UserID: user.ID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to revoke session")
+		return
+	}
+	if n == 0 {
+		respondWithError(w, http.StatusNotFound, "Session not found")
+		return
+	}
+
+	if currentID, ok := sessionIDFromContext(r.Context()); ok && currentID == sessionID {
+		clearSessionCookies(w, r)
+	}
+	respondWithJSON(w, http.StatusOK, struct{}{})
+}
+
+// handlerSetPasswordAuthed sets or changes the password of the account.
+// Setting the first password requires a session or an API key holding every
+// scope. Changing an existing password requires the current one and ends
+// every other session.
+func (cfg *apiConfig) handlerSetPasswordAuthed(w http.ResponseWriter, r *http.Request, user database.User) {
+	type parameters struct {
+		CurrentPassword string `json:"current_password"`
+		NewPassword     string `json:"new_password"`
+	}
+
+	params := parameters{}
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to decode parameters")
+		return
+	}
+
+	if user.PasswordHash.Valid {
+		if err := auth.CheckPassword(user.PasswordHash.String, params.CurrentPassword); err != nil {
+			respondWithError(w, http.StatusForbidden, "Current password is wrong")
+			return
+		}
+	} else {
+		// Logging in with the password grants every scope, so the first one
+		// can only be set by a credential that already holds them all.
+		granted := scopesFromContext(r.Context())
+		for _, scope := range userScopes {
+			if !hasScope(granted, scope) {
+				respondWithError(w, http.StatusForbidden, "Setting the first password requires a session or an API key with every scope")
+				return
+			}
+		}
+	}
+
+	hash, err := auth.HashPassword(params.NewPassword)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := cfg.DB.SetUserPassword(r.Context(), database.SetUserPasswordParams{
+		ID:           user.ID,
+		PasswordHash: sql.NullString{String: hash, Valid: true},
+	}); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to set password")
+		return
+	}
+
+	currentID, ok := sessionIDFromContext(r.Context())
+	if err := cfg.DB.DeleteOtherSessions(r.Context(), database.DeleteOtherSessionsParams{
+		UserID: user.ID,
+		KeepID: uuid.NullUUID{UUID: currentID, Valid: ok},
+	}); err != nil {
+		log.Printf("Failed to end other sessions of user %v: %v", user.ID, err)
+	}
+
+	respondWithJSON(w, http.StatusOK, struct{}{})
+}
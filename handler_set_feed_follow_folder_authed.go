@@ -0,0 +1,52 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/JustinLi007/rss-aggregator/internal/database"
+	"github.com/google/uuid"
+)
+
+// handlerSetFeedFollowFolderAuthed moves a follow into one of the user's
+// folders, or back to the top level when folder_id is null.
+func (cfg *apiConfig) handlerSetFeedFollowFolderAuthed(w http.ResponseWriter, r *http.Request, user database.User) {
+	feedFollowID, err := parseUUIDPathValue(r, "feedFollowID")
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	type parameters struct {
+		FolderID *uuid.UUID `json:"folder_id"`
+	}
+
+	params := parameters{}
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to decode parameters")
+		return
+	}
+
+	folderID := uuid.NullUUID{}
+	if params.FolderID != nil {
+		folderID = uuid.NullUUID{UUID: *params.FolderID, Valid: true}
+	}
+
+	feedFollow, err := cfg.DB.SetFeedFollowFolder(r.Context(), database.SetFeedFollowFolderParams{
+		FolderID: folderID,
+		ID:       feedFollowID,
+		UserID:   user.ID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "Feed follow or folder not found")
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to update feed follow")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, databaseFeedFollowToFeedFollow(feedFollow))
+}
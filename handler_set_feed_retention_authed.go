@@ -0,0 +1,72 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/JustinLi007/rss-aggregator/internal/database"
+)
+
+// handlerSetFeedRetentionAuthed replaces the retention overrides of a feed.
+// Omitted or null limits fall back to the server-wide policy. Like other
+// feed changes it is restricted to the feed's owner and admins.
+func (cfg *apiConfig) handlerSetFeedRetentionAuthed(w http.ResponseWriter, r *http.Request, user database.User) {
+	feedID, err := parseUUIDPathValue(r, "feedID")
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	type parameters struct {
+		MaxAgeDays *int32 `json:"max_age_days"`
+		MaxPosts   *int32 `json:"max_posts"`
+	}
+
+	params := parameters{}
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to decode parameters")
+		return
+	}
+
+	feed, err := cfg.DB.GetFeedByID(r.Context(), feedID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "Feed not found")
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to retrieve feed")
+		return
+	}
+	if !canManageFeed(r.Context(), user, feed) {
+		respondWithError(w, http.StatusForbidden, "Only the feed owner can change it")
+		return
+	}
+
+	setParams := database.SetFeedRetentionParams{
+		ID: feedID,
+	}
+	if params.MaxAgeDays != nil {
+		if *params.MaxAgeDays <= 0 {
+			respondWithError(w, http.StatusBadRequest, "max_age_days must be positive")
+			return
+		}
+		setParams.RetentionMaxAgeDays = sql.NullInt32{Int32: *params.MaxAgeDays, Valid: true}
+	}
+	if params.MaxPosts != nil {
+		if *params.MaxPosts <= 0 {
+			respondWithError(w, http.StatusBadRequest, "max_posts must be positive")
+			return
+		}
+		setParams.RetentionMaxPosts = sql.NullInt32{Int32: *params.MaxPosts, Valid: true}
+	}
+
+	feed, err = cfg.DB.SetFeedRetention(r.Context(), setParams)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to update feed")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, databaseFeedToFeed(feed))
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"crypto/subtle"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/JustinLi007/rss-aggregator/internal/oidc"
+)
+
+// ssoCookieName holds the state, nonce and PKCE verifier of a login in
+// progress, binding the callback to the browser that started it.
+const ssoCookieName = "oidc_login"
+
+const ssoLoginTimeout = 10 * time.Minute
+
+// handlerSSOLogin sends the browser to the identity provider. In invite-only
+// mode, first logins must pass an invite_code query parameter.
+func (cfg *apiConfig) handlerSSOLogin(w http.ResponseWriter, r *http.Request) {
+	if cfg.SSO == nil {
+		respondWithError(w, http.StatusNotFound, "Single sign-on is not configured")
+		return
+	}
+
+	// The invite code, needed to sign up in invite-only mode, rides along
+	// in the cookie.
+	values := make([]string, 4)
+	values[3] = r.URL.Query().Get("invite_code")
+	if strings.Contains(values[3], ".") {
+		respondWithError(w, http.StatusBadRequest, "Invalid invite code")
+		return
+	}
+	for i := range values[:3] {
+		value, err := oidc.GenerateVerifier()
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Failed to start login")
+			return
+		}
+		values[i] = value
+	}
+	state, nonce, verifier := values[0], values[1], values[2]
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     ssoCookieName,
+		Value:    strings.Join(values, "."),
+		Path:     "/v1/oidc",
+		MaxAge:   int(ssoLoginTimeout.Seconds()),
+		HttpOnly: true,
+		Secure:   isSecureRequest(r),
+		// Lax still sends the cookie on the provider's top-level redirect back.
+		SameSite: http.SameSiteLaxMode,
+	})
+	http.Redirect(w, r, cfg.SSO.provider.AuthCodeURL(state, nonce, verifier), http.StatusFound)
+}
+
+// handlerSSOCallback finishes a login at the identity provider, creating the
+// user on first login, and starts a browser session.
+func (cfg *apiConfig) handlerSSOCallback(w http.ResponseWriter, r *http.Request) {
+	if cfg.SSO == nil {
+		respondWithError(w, http.StatusNotFound, "Single sign-on is not configured")
+		return
+	}
+
+	// The login can only be finished once.
+	http.SetCookie(w, &http.Cookie{
+		Name:     ssoCookieName,
+		Value:    "",
+		Path:     "/v1/oidc",
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   isSecureRequest(r),
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	query := r.URL.Query()
+	if errorCode := query.Get("error"); errorCode != "" {
+		respondWithError(w, http.StatusUnauthorized, "Identity provider refused login: "+errorCode)
+		return
+	}
+
+	cookie, err := r.Cookie(ssoCookieName)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "No login in progress")
+		return
+	}
+	values := strings.Split(cookie.Value, ".")
+	if len(values) != 4 || subtle.ConstantTimeCompare([]byte(query.Get("state")), []byte(values[0])) != 1 {
+		respondWithError(w, http.StatusBadRequest, "Invalid login state")
+		return
+	}
+	nonce, verifier, inviteCode := values[1], values[2], values[3]
+
+	claims, err := cfg.SSO.provider.Exchange(r.Context(), query.Get("code"), verifier, nonce)
+	if err != nil {
+		log.Printf("Single sign-on failed: %v", err)
+		respondWithError(w, http.StatusUnauthorized, "Single sign-on failed")
+		return
+	}
+
+	user, err := cfg.provisionSSOUser(r.Context(), claims, strings.TrimSpace(inviteCode))
+	if errors.Is(err, errRegistrationClosed) || errors.Is(err, errInvalidInvite) {
+		respondWithError(w, http.StatusForbidden, err.Error())
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to provision user")
+		return
+	}
+	if user.DisabledAt.Valid {
+		respondWithError(w, http.StatusForbidden, "Account is disabled")
+		return
+	}
+
+	if _, err := cfg.startSession(w, r, user); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to create session")
+		return
+	}
+
+	http.Redirect(w, r, cfg.SSO.postLoginURL, http.StatusFound)
+}
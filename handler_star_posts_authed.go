@@ -0,0 +1,58 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/JustinLi007/rss-aggregator/internal/database"
+	"github.com/google/uuid"
+)
+
+// handlerStarPostAuthed stars a post of a followed feed. Starred posts stay
+// starred after the feed is unfollowed.
+func (cfg *apiConfig) handlerStarPostAuthed(w http.ResponseWriter, r *http.Request, user database.User) {
+	postID, err := parseUUIDPathValue(r, "postID")
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	followed, err := cfg.postsFollowed(r.Context(), user.ID, []uuid.UUID{postID})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to star post")
+		return
+	}
+	if !followed {
+		respondWithError(w, http.StatusNotFound, "Post not found")
+		return
+	}
+
+	updated, err := cfg.DB.StarPost(r.Context(), database.StarPostParams{
+		UserID: user.ID,
+		PostID: postID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to star post")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, markPostsPayload{Updated: updated})
+}
+
+func (cfg *apiConfig) handlerUnstarPostAuthed(w http.ResponseWriter, r *http.Request, user database.User) {
+	postID, err := parseUUIDPathValue(r, "postID")
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	updated, err := cfg.DB.UnstarPost(r.Context(), database.UnstarPostParams{
+		UserID: user.ID,
+		PostID: postID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to unstar post")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, markPostsPayload{Updated: updated})
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/JustinLi007/rss-aggregator/internal/database"
+	"github.com/JustinLi007/rss-aggregator/internal/feedurl"
+)
+
+// handlerUpdateFeedAuthed renames a feed or points it at a new URL. Only the
+// feed's owner and admins may change it, and the change is seen by every
+// follower. A new URL makes the feed due for fetching right away.
+func (cfg *apiConfig) handlerUpdateFeedAuthed(w http.ResponseWriter, r *http.Request, user database.User) {
+	feedID, err := parseUUIDPathValue(r, "feedID")
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	type parameters struct {
+		Name *string `json:"name"`
+		URL  *string `json:"url"`
+	}
+
+	params := parameters{}
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to decode parameters")
+		return
+	}
+
+	feed, err := cfg.DB.GetFeedByID(r.Context(), feedID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "Feed not found")
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to retrieve feed")
+		return
+	}
+	if !canManageFeed(r.Context(), user, feed) {
+		respondWithError(w, http.StatusForbidden, "Only the feed owner can change it")
+		return
+	}
+
+	updateParams := database.UpdateFeedParams{
+		ID: feedID,
+	}
+	if params.Name != nil {
+		name := strings.TrimSpace(*params.Name)
+		if name == "" {
+			respondWithError(w, http.StatusBadRequest, "Feed name is required")
+			return
+		}
+		updateParams.Name = sql.NullString{String: name, Valid: true}
+	}
+	if params.URL != nil {
+		feedURL, err := feedurl.Normalize(*params.URL)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if feedURL != feed.Url {
+			updateParams.Url = sql.NullString{String: feedURL, Valid: true}
+			updateParams.CanonicalUrl = sql.NullString{String: feedurl.Canonical(feedURL), Valid: true}
+		}
+	}
+
+	feed, err = cfg.DB.UpdateFeed(r.Context(), updateParams)
+	if err != nil {
+		if isDuplicateKeyError(err) {
+			respondWithError(w, http.StatusConflict, "Another feed already uses this URL")
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to update feed")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, databaseFeedToFeed(feed))
+}
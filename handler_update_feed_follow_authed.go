@@ -0,0 +1,82 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/JustinLi007/rss-aggregator/internal/database"
+)
+
+const (
+	notificationsNone  = "none"
+	notificationsRules = "rules"
+	notificationsAll   = "all"
+)
+
+// handlerUpdateFeedFollowAuthed edits the user's own settings for a follow.
+// Omitted fields are left untouched and an empty title restores the feed's
+// original name. Hidden follows are left out of the main timeline but still
+// show up when filtering by feed or folder. Notifications "rules", the
+// default, notifies about the posts the user's notify filter rules match,
+// "all" about every new post of the feed, and "none" silences the feed, notify
+// rules included. Muted follows don't count towards folder unread counts and
+// never notify, whatever their notifications preference.
+func (cfg *apiConfig) handlerUpdateFeedFollowAuthed(w http.ResponseWriter, r *http.Request, user database.User) {
+	feedFollowID, err := parseUUIDPathValue(r, "feedFollowID")
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	type parameters struct {
+		Title            *string `json:"title"`
+		Muted            *bool   `json:"muted"`
+		Notifications    *string `json:"notifications"`
+		SortPriority     *int32  `json:"sort_priority"`
+		HideFromTimeline *bool   `json:"hide_from_timeline"`
+	}
+
+	params := parameters{}
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to decode parameters")
+		return
+	}
+
+	updateParams := database.UpdateFeedFollowSettingsParams{
+		ID:     feedFollowID,
+		UserID: user.ID,
+	}
+	if params.Title != nil {
+		updateParams.Title = sql.NullString{String: *params.Title, Valid: true}
+	}
+	if params.Muted != nil {
+		updateParams.Muted = sql.NullBool{Bool: *params.Muted, Valid: true}
+	}
+	if params.Notifications != nil {
+		if *params.Notifications != notificationsNone && *params.Notifications != notificationsRules && *params.Notifications != notificationsAll {
+			respondWithError(w, http.StatusBadRequest, "Invalid notifications preference")
+			return
+		}
+		updateParams.Notifications = sql.NullString{String: *params.Notifications, Valid: true}
+	}
+	if params.SortPriority != nil {
+		updateParams.SortPriority = sql.NullInt32{Int32: *params.SortPriority, Valid: true}
+	}
+	if params.HideFromTimeline != nil {
+		updateParams.HideFromTimeline = sql.NullBool{Bool: *params.HideFromTimeline, Valid: true}
+	}
+
+	feedFollow, err := cfg.DB.UpdateFeedFollowSettings(r.Context(), updateParams)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "Feed follow not found")
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to update feed follow")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, databaseFeedFollowToFeedFollow(feedFollow))
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/JustinLi007/rss-aggregator/internal/database"
+	"github.com/google/uuid"
+)
+
+func (cfg *apiConfig) handlerUpdateFolderAuthed(w http.ResponseWriter, r *http.Request, user database.User) {
+	folderID, err := parseUUIDPathValue(r, "folderID")
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	type parameters struct {
+		Name     *string `json:"name"`
+		Position *int32  `json:"position"`
+	}
+
+	params := parameters{}
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to decode parameters")
+		return
+	}
+
+	updateParams := database.UpdateFolderParams{
+		ID:     folderID,
+		UserID: user.ID,
+	}
+	if params.Name != nil {
+		name := strings.TrimSpace(*params.Name)
+		if name == "" {
+			respondWithError(w, http.StatusBadRequest, "Folder name is required")
+			return
+		}
+		updateParams.Name = sql.NullString{String: name, Valid: true}
+	}
+	if params.Position != nil {
+		updateParams.Position = sql.NullInt32{Int32: *params.Position, Valid: true}
+	}
+
+	folder, err := cfg.DB.UpdateFolder(r.Context(), updateParams)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "Folder not found")
+			return
+		}
+		if isDuplicateKeyError(err) {
+			respondWithError(w, http.StatusConflict, "Folder already exists")
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to update folder")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, databaseFolderToFolder(folder))
+}
+
+// handlerReorderFoldersAuthed assigns positions to the user's folders
+// following the order of the submitted IDs.
+func (cfg *apiConfig) handlerReorderFoldersAuthed(w http.ResponseWriter, r *http.Request, user database.User) {
+	type parameters struct {
+		FolderIDs []uuid.UUID `json:"folder_ids"`
+	}
+
+	params := parameters{}
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to decode parameters")
+		return
+	}
+
+	if _, err := cfg.DB.ReorderFolders(r.Context(), database.ReorderFoldersParams{
+		FolderIds: params.FolderIDs,
+		UserID:    user.ID,
+	}); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to reorder folders")
+		return
+	}
+
+	folders, err := cfg.DB.GetFoldersByUser(r.Context(), user.ID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to retrieve folders")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, databaseFoldersToFolders(folders))
+}
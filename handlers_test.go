@@ -19,9 +19,11 @@ type createFeedPayload struct {
 	FeedFollow UsersFeedsFollow `json:"feed_follow"`
 }
 
-var testUserName = "Sample User"
+// Names must be unique, so every run signs up a new user.
+var testUserName = fmt.Sprintf("Sample User %v", time.Now().UnixNano())
 var testFeedName = "Sample Feed"
 var testFeedURL = "www.url.com"
+var testFeedNormalizedURL = "https://www.url.com"
 var testUser = User{}
 var testFeedCreatePayload = createFeedPayload{}
 var testFeedFollow = UsersFeedsFollow{}
@@ -122,8 +124,8 @@ func compareUser(expected User, actual User, t *testing.T) {
 	if actual.Name != expected.Name {
 		t.Errorf("Expected name %v, got %v", expected.Name, actual.Name)
 	}
-	if actual.ApiKey != expected.ApiKey {
-		t.Errorf("Expected ApiKey %v, got %v", expected.ApiKey, actual.ApiKey)
+	if actual.ApiKey != "" {
+		t.Errorf("Expected ApiKey to be hidden, got %v", actual.ApiKey)
 	}
 }
 
@@ -243,7 +245,7 @@ func TestCreateFeedsEndpoint(t *testing.T) {
 	}
 	testFeedCreatePayload = respPayload
 
-	feedIsSet(respPayload.Feed, testUser, testFeedName, testFeedURL, t)
+	feedIsSet(respPayload.Feed, testUser, testFeedName, testFeedNormalizedURL, t)
 	userFeedFollowIsSet(respPayload.FeedFollow, respPayload.Feed, testUser, t)
 }
 
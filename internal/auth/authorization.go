@@ -1,6 +1,9 @@
 package auth
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"net/http"
 	"strings"
@@ -21,3 +24,31 @@ func GetApiKeyToken(header http.Header) (string, error) {
 
 	return apiKey, nil
 }
+
+// GenerateToken returns a random 256 bit token encoded as hex.
+func GenerateToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// APIKeyPrefixLength is the number of leading characters of an API key that
+// are stored in clear to find the key and tell keys apart.
+const APIKeyPrefixLength = 8
+
+// HashToken returns the digest under which API keys and session tokens are
+// stored. They are random 256 bit tokens, so a plain SHA-256 is enough.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// APIKeyPrefix returns the stored lookup prefix of an API key.
+func APIKeyPrefix(apiKey string) string {
+	if len(apiKey) < APIKeyPrefixLength {
+		return apiKey
+	}
+	return apiKey[:APIKeyPrefixLength]
+}
@@ -0,0 +1,52 @@
+package auth
+
+import (
+	"errors"
+	"unicode/utf8"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	MinPasswordLength = 8
+	// bcrypt ignores everything past 72 bytes.
+	MaxPasswordLength = 72
+)
+
+var (
+	ErrPasswordTooShort = errors.New("Password must be at least 8 characters long")
+	ErrPasswordTooLong  = errors.New("Password must be at most 72 bytes long")
+	ErrWrongPassword    = errors.New("Wrong name or password")
+)
+
+// dummyHash is compared against when the account doesn't exist so that
+// failed logins take the same time whether or not the name is known.
+var dummyHash, _ = bcrypt.GenerateFromPassword([]byte("dummy password"), bcrypt.DefaultCost)
+
+// HashPassword validates password and returns its bcrypt hash.
+func HashPassword(password string) (string, error) {
+	if utf8.RuneCountInString(password) < MinPasswordLength {
+		return "", ErrPasswordTooShort
+	}
+	if len(password) > MaxPasswordLength {
+		return "", ErrPasswordTooLong
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// CheckPassword returns ErrWrongPassword unless password matches hash. An
+// empty hash never matches but costs as much as a real comparison.
+func CheckPassword(hash, password string) error {
+	if hash == "" {
+		bcrypt.CompareHashAndPassword(dummyHash, []byte(password))
+		return ErrWrongPassword
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)); err != nil {
+		return ErrWrongPassword
+	}
+	return nil
+}
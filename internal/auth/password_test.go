@@ -0,0 +1,34 @@
+package auth
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestHashPassword(t *testing.T) {
+	if _, err := HashPassword("short"); !errors.Is(err, ErrPasswordTooShort) {
+		t.Errorf("HashPassword(short) error = %v, want %v", err, ErrPasswordTooShort)
+	}
+	if _, err := HashPassword(strings.Repeat("a", 73)); !errors.Is(err, ErrPasswordTooLong) {
+		t.Errorf("HashPassword(long) error = %v, want %v", err, ErrPasswordTooLong)
+	}
+
+	hash, err := HashPassword("correct horse battery")
+	if err != nil {
+		t.Fatalf("HashPassword: %v", err)
+	}
+	if hash == "correct horse battery" {
+		t.Fatalf("HashPassword returned the password")
+	}
+
+	if err := CheckPassword(hash, "correct horse battery"); err != nil {
+		t.Errorf("CheckPassword(right password) = %v", err)
+	}
+	if err := CheckPassword(hash, "wrong horse battery"); !errors.Is(err, ErrWrongPassword) {
+		t.Errorf("CheckPassword(wrong password) = %v, want %v", err, ErrWrongPassword)
+	}
+	if err := CheckPassword("", "correct horse battery"); !errors.Is(err, ErrWrongPassword) {
+		t.Errorf("CheckPassword(no hash) = %v, want %v", err, ErrWrongPassword)
+	}
+}
@@ -7,24 +7,26 @@ package database
 
 import (
 	"context"
+	"database/sql"
 	"time"
 
 	"github.com/google/uuid"
 )
 
 const createFeed = `-- name: CreateFeed :one
-INSERT INTO feeds(id, created_at, updated_at, name, url, user_id)
-VALUES($1, $2, $3, $4, $5, $6)
-RETURNING id, created_at, updated_at, name, url, user_id, last_fetched_at
+INSERT INTO feeds(id, created_at, updated_at, name, url, user_id, canonical_url)
+VALUES($1, $2, $3, $4, $5, $6, $7)
+RETURNING id, created_at, updated_at, name, url, user_id, last_fetched_at, canonical_url, site_url, dormant_since, retention_max_age_days, retention_max_posts
 `
 
 type CreateFeedParams struct {
-	ID        uuid.UUID
-	CreatedAt time.Time
-	UpdatedAt time.Time
-	Name      string
-	Url       string
-	UserID    uuid.UUID
+	ID           uuid.UUID
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	Name         string
+	Url          string
+	UserID       uuid.UUID
+	CanonicalUrl string
 }
 
 func (q *Queries) CreateFeed(ctx context.Context, arg CreateFeedParams) (Feed, error) {
@@ -35,6 +37,7 @@ func (q *Queries) CreateFeed(ctx context.Context, arg CreateFeedParams) (Feed, e
 		arg.Name,
 		arg.Url,
 		arg.UserID,
+		arg.CanonicalUrl,
 	)
 	var i Feed
 	err := row.Scan(
@@ -45,12 +48,80 @@ func (q *Queries) CreateFeed(ctx context.Context, arg CreateFeedParams) (Feed, e
 		&i.Url,
 		&i.UserID,
 		&i.LastFetchedAt,
+		&i.CanonicalUrl,
+		&i.SiteUrl,
+		&i.DormantSince,
+		&i.RetentionMaxAgeDays,
+		&i.RetentionMaxPosts,
+	)
+	return i, err
+}
+
+const deleteFeed = `-- name: DeleteFeed :execrows
+DELETE FROM feeds
+WHERE id = $1
+`
+
+func (q *Queries) DeleteFeed(ctx context.Context, id uuid.UUID) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteFeed, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const getFeedByCanonicalURL = `-- name: GetFeedByCanonicalURL :one
+SELECT id, created_at, updated_at, name, url, user_id, last_fetched_at, canonical_url, site_url, dormant_since, retention_max_age_days, retention_max_posts FROM feeds
+WHERE canonical_url = $1
+`
+
+func (q *Queries) GetFeedByCanonicalURL(ctx context.Context, canonicalUrl string) (Feed, error) {
+	row := q.db.QueryRowContext(ctx, getFeedByCanonicalURL, canonicalUrl)
+	var i Feed
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+		&i.Url,
+		&i.UserID,
+		&i.LastFetchedAt,
+		&i.CanonicalUrl,
+		&i.SiteUrl,
+		&i.DormantSince,
+		&i.RetentionMaxAgeDays,
+		&i.RetentionMaxPosts,
+	)
+	return i, err
+}
+
+const getFeedByID = `-- name: GetFeedByID :one
+SELECT id, created_at, updated_at, name, url, user_id, last_fetched_at, canonical_url, site_url, dormant_since, retention_max_age_days, retention_max_posts FROM feeds
+WHERE id = $1
+`
+
+func (q *Queries) GetFeedByID(ctx context.Context, id uuid.UUID) (Feed, error) {
+	row := q.db.QueryRowContext(ctx, getFeedByID, id)
+	var i Feed
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+		&i.Url,
+		&i.UserID,
+		&i.LastFetchedAt,
+		&i.CanonicalUrl,
+		&i.SiteUrl,
+		&i.DormantSince,
+		&i.RetentionMaxAgeDays,
+		&i.RetentionMaxPosts,
 	)
 	return i, err
 }
 
 const getFeeds = `-- name: GetFeeds :many
-SELECT id, created_at, updated_at, name, url, user_id, last_fetched_at FROM feeds
+SELECT id, created_at, updated_at, name, url, user_id, last_fetched_at, canonical_url, site_url, dormant_since, retention_max_age_days, retention_max_posts FROM feeds
 `
 
 func (q *Queries) GetFeeds(ctx context.Context) ([]Feed, error) {
@@ -70,6 +141,11 @@ func (q *Queries) GetFeeds(ctx context.Context) ([]Feed, error) {
 			&i.Url,
 			&i.UserID,
 			&i.LastFetchedAt,
+			&i.CanonicalUrl,
+			&i.SiteUrl,
+			&i.DormantSince,
+			&i.RetentionMaxAgeDays,
+			&i.RetentionMaxPosts,
 		); err != nil {
 			return nil, err
 		}
@@ -85,7 +161,11 @@ func (q *Queries) GetFeeds(ctx context.Context) ([]Feed, error) {
 }
 
 const getNextFeedsToFetch = `-- name: GetNextFeedsToFetch :many
-SELECT id, created_at, updated_at, name, url, user_id, last_fetched_at FROM feeds
+SELECT id, created_at, updated_at, name, url, user_id, last_fetched_at, canonical_url, site_url, dormant_since, retention_max_age_days, retention_max_posts FROM feeds
+WHERE EXISTS (
+    SELECT 1 FROM users_feeds_follows
+    WHERE users_feeds_follows.feed_id = feeds.id
+)
 ORDER BY last_fetched_at ASC NULLS FIRST
 LIMIT $1
 `
@@ -107,6 +187,11 @@ func (q *Queries) GetNextFeedsToFetch(ctx context.Context, limit int32) ([]Feed,
 			&i.Url,
 			&i.UserID,
 			&i.LastFetchedAt,
+			&i.CanonicalUrl,
+			&i.SiteUrl,
+			&i.DormantSince,
+			&i.RetentionMaxAgeDays,
+			&i.RetentionMaxPosts,
 		); err != nil {
 			return nil, err
 		}
@@ -125,7 +210,7 @@ const markFeedFetched = `-- name: MarkFeedFetched :one
 UPDATE feeds
 SET last_fetched_at = NOW(), updated_at = NOW()
 WHERE id = $1
-RETURNING id, created_at, updated_at, name, url, user_id, last_fetched_at
+RETURNING id, created_at, updated_at, name, url, user_id, last_fetched_at, canonical_url, site_url, dormant_since, retention_max_age_days, retention_max_posts
 `
 
 func (q *Queries) MarkFeedFetched(ctx context.Context, id uuid.UUID) (Feed, error) {
@@ -139,6 +224,206 @@ func (q *Queries) MarkFeedFetched(ctx context.Context, id uuid.UUID) (Feed, erro
 		&i.Url,
 		&i.UserID,
 		&i.LastFetchedAt,
+		&i.CanonicalUrl,
+		&i.SiteUrl,
+		&i.DormantSince,
+		&i.RetentionMaxAgeDays,
+		&i.RetentionMaxPosts,
+	)
+	return i, err
+}
+
+const markOrphanedFeedsDormant = `-- name: MarkOrphanedFeedsDormant :execrows
+UPDATE feeds
+SET dormant_since = NOW(), updated_at = NOW()
+WHERE dormant_since IS NULL
+AND NOT EXISTS (
+    SELECT 1 FROM users_feeds_follows
+    WHERE users_feeds_follows.feed_id = feeds.id
+)
+`
+
+func (q *Queries) MarkOrphanedFeedsDormant(ctx context.Context) (int64, error) {
+	result, err := q.db.ExecContext(ctx, markOrphanedFeedsDormant)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const purgeDormantFeeds = `-- name: PurgeDormantFeeds :execrows
+DELETE FROM feeds
+WHERE dormant_since < $1::timestamp
+AND NOT EXISTS (
+    SELECT 1 FROM users_feeds_follows
+    WHERE users_feeds_follows.feed_id = feeds.id
+)
+`
+
+func (q *Queries) PurgeDormantFeeds(ctx context.Context, dormantBefore time.Time) (int64, error) {
+	result, err := q.db.ExecContext(ctx, purgeDormantFeeds, dormantBefore)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const resumeFeed = `-- name: ResumeFeed :exec
+UPDATE feeds
+SET dormant_since = NULL, updated_at = NOW()
+WHERE id = $1 AND dormant_since IS NOT NULL
+`
+
+func (q *Queries) ResumeFeed(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, resumeFeed, id)
+	return err
+}
+
+const resumeFollowedFeeds = `-- name: ResumeFollowedFeeds :execrows
+UPDATE feeds
+SET dormant_since = NULL, updated_at = NOW()
+WHERE dormant_since IS NOT NULL
+AND EXISTS (
+    SELECT 1 FROM users_feeds_follows
+    WHERE users_feeds_follows.feed_id = feeds.id
+)
+`
+
+func (q *Queries) ResumeFollowedFeeds(ctx context.Context) (int64, error) {
+	result, err := q.db.ExecContext(ctx, resumeFollowedFeeds)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const setFeedRetention = `-- name: SetFeedRetention :one
+UPDATE feeds
+SET retention_max_age_days = $2, retention_max_posts = $3, updated_at = NOW()
+WHERE id = $1
+RETURNING id, created_at, updated_at, name, url, user_id, last_fetched_at, canonical_url, site_url, dormant_since, retention_max_age_days, retention_max_posts
+`
+
+type SetFeedRetentionParams struct {
+	ID                  uuid.UUID
+	RetentionMaxAgeDays sql.NullInt32
+	RetentionMaxPosts   sql.NullInt32
+}
+
+func (q *Queries) SetFeedRetention(ctx context.Context, arg SetFeedRetentionParams) (Feed, error) {
+	row := q.db.QueryRowContext(ctx, setFeedRetention, arg.ID, arg.RetentionMaxAgeDays, arg.RetentionMaxPosts)
+	var i Feed
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+		&i.Url,
+		&i.UserID,
+		&i.LastFetchedAt,
+		&i.CanonicalUrl,
+		&i.SiteUrl,
+		&i.DormantSince,
+		&i.RetentionMaxAgeDays,
+		&i.RetentionMaxPosts,
+	)
+	return i, err
+}
+
+const setFeedSiteURL = `-- name: SetFeedSiteURL :exec
+UPDATE feeds
+SET site_url = $2
+WHERE id = $1
+`
+
+type SetFeedSiteURLParams struct {
+	ID      uuid.UUID
+	SiteUrl sql.NullString
+}
+
+func (q *Queries) SetFeedSiteURL(ctx context.Context, arg SetFeedSiteURLParams) error {
+	_, err := q.db.ExecContext(ctx, setFeedSiteURL, arg.ID, arg.SiteUrl)
+	return err
+}
+
+const transferFeedOwnership = `-- name: TransferFeedOwnership :one
+UPDATE feeds
+SET user_id = next_owner.user_id, updated_at = NOW()
+FROM (
+    SELECT users_feeds_follows.user_id FROM users_feeds_follows
+    WHERE users_feeds_follows.feed_id = $1
+    AND users_feeds_follows.user_id <> $2
+    ORDER BY users_feeds_follows.created_at ASC
+    LIMIT 1
+) AS next_owner
+WHERE feeds.id = $1
+RETURNING feeds.id, feeds.created_at, feeds.updated_at, feeds.name, feeds.url, feeds.user_id, feeds.last_fetched_at, feeds.canonical_url, feeds.site_url, feeds.dormant_since, feeds.retention_max_age_days, feeds.retention_max_posts
+`
+
+type TransferFeedOwnershipParams struct {
+	ID     uuid.UUID
+	UserID uuid.UUID
+}
+
+func (q *Queries) TransferFeedOwnership(ctx context.Context, arg TransferFeedOwnershipParams) (Feed, error) {
+	row := q.db.QueryRowContext(ctx, transferFeedOwnership, arg.ID, arg.UserID)
+	var i Feed
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+		&i.Url,
+		&i.UserID,
+		&i.LastFetchedAt,
+		&i.CanonicalUrl,
+		&i.SiteUrl,
+		&i.DormantSince,
+		&i.RetentionMaxAgeDays,
+		&i.RetentionMaxPosts,
+	)
+	return i, err
+}
+
+const updateFeed = `-- name: UpdateFeed :one
+UPDATE feeds
+SET name = COALESCE($1, name),
+url = COALESCE($2, url),
+canonical_url = COALESCE($3, canonical_url),
+last_fetched_at = CASE WHEN $2::text IS NULL THEN last_fetched_at ELSE NULL END,
+updated_at = NOW()
+WHERE id = $4
+RETURNING id, created_at, updated_at, name, url, user_id, last_fetched_at, canonical_url, site_url, dormant_since, retention_max_age_days, retention_max_posts
+`
+
+type UpdateFeedParams struct {
+	Name         sql.NullString
+	Url          sql.NullString
+	CanonicalUrl sql.NullString
+	ID           uuid.UUID
+}
+
+func (q *Queries) UpdateFeed(ctx context.Context, arg UpdateFeedParams) (Feed, error) {
+	row := q.db.QueryRowContext(ctx, updateFeed,
+		arg.Name,
+		arg.Url,
+		arg.CanonicalUrl,
+		arg.ID,
+	)
+	var i Feed
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+		&i.Url,
+		&i.UserID,
+		&i.LastFetchedAt,
+		&i.CanonicalUrl,
+		&i.SiteUrl,
+		&i.DormantSince,
+		&i.RetentionMaxAgeDays,
+		&i.RetentionMaxPosts,
 	)
 	return i, err
 }
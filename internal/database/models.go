@@ -6,19 +6,99 @@ package database
 
 import (
 	"database/sql"
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+type ApiKey struct {
+	ID         uuid.UUID
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	Name       string
+	LastUsedAt sql.NullTime
+	ExpiresAt  sql.NullTime
+	RevokedAt  sql.NullTime
+	UserID     uuid.UUID
+	KeyHash    string
+	Prefix     string
+	Scopes     []string
+}
+
 type Feed struct {
-	ID            uuid.UUID
-	CreatedAt     time.Time
-	UpdatedAt     time.Time
-	Name          string
-	Url           string
-	UserID        uuid.UUID
-	LastFetchedAt sql.NullTime
+	ID                  uuid.UUID
+	CreatedAt           time.Time
+	UpdatedAt           time.Time
+	Name                string
+	Url                 string
+	UserID              uuid.UUID
+	LastFetchedAt       sql.NullTime
+	CanonicalUrl        string
+	SiteUrl             sql.NullString
+	DormantSince        sql.NullTime
+	RetentionMaxAgeDays sql.NullInt32
+	RetentionMaxPosts   sql.NullInt32
+}
+
+type FilterRule struct {
+	ID         uuid.UUID
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	Name       string
+	Conditions json.RawMessage
+	MatchAll   bool
+	Actions    []string
+	Tag        sql.NullString
+	Enabled    bool
+	UserID     uuid.UUID
+}
+
+type Folder struct {
+	ID        uuid.UUID
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	Name      string
+	Position  int32
+	UserID    uuid.UUID
+}
+
+type Invite struct {
+	ID        uuid.UUID
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	Code      string
+	MaxUses   int32
+	Uses      int32
+	ExpiresAt sql.NullTime
+	RevokedAt sql.NullTime
+	CreatedBy uuid.NullUUID
+}
+
+type Label struct {
+	ID        uuid.UUID
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	Name      string
+	UserID    uuid.UUID
+}
+
+type Notification struct {
+	ID           uuid.UUID
+	CreatedAt    time.Time
+	UserID       uuid.UUID
+	PostID       uuid.UUID
+	FilterRuleID uuid.NullUUID
+}
+
+type OutputFeedToken struct {
+	ID        uuid.UUID
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	Name      string
+	Token     string
+	RevokedAt sql.NullTime
+	UserID    uuid.UUID
 }
 
 type Post struct {
@@ -29,21 +109,89 @@ type Post struct {
 	Url         string
 	Description sql.NullString
 	PublishedAt time.Time
-	FeedID      uuid.UUID
+	FeedID      uuid.NullUUID
+	Content     sql.NullString
+	Author      sql.NullString
+	Categories  []string
 }
 
-type User struct {
+type PostsLabel struct {
+	LabelID   uuid.UUID
+	PostID    uuid.UUID
+	CreatedAt time.Time
+}
+
+type PrunedPost struct {
+	FeedID   uuid.UUID
+	Url      string
+	PrunedAt time.Time
+}
+
+type SavedSearch struct {
 	ID        uuid.UUID
 	CreatedAt time.Time
 	UpdatedAt time.Time
 	Name      string
-	ApiKey    string
+	Query     string
+	TsQuery   string
+	UserID    uuid.UUID
 }
 
-type UsersFeedsFollow struct {
+type SavedSearchMatch struct {
+	SavedSearchID uuid.UUID
+	PostID        uuid.UUID
+	CreatedAt     time.Time
+}
+
+type Session struct {
+	ID         uuid.UUID
+	CreatedAt  time.Time
+	LastSeenAt time.Time
+	ExpiresAt  time.Time
+	TokenHash  string
+	CsrfToken  string
+	UserAgent  sql.NullString
+	UserID     uuid.UUID
+}
+
+type User struct {
+	ID           uuid.UUID
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	Name         string
+	Role         string
+	PasswordHash sql.NullString
+	DisabledAt   sql.NullTime
+}
+
+type UserIdentity struct {
 	ID        uuid.UUID
 	CreatedAt time.Time
-	UpdatedAt time.Time
+	Issuer    string
+	Subject   string
+	UserID    uuid.UUID
+}
+
+type UsersFeedsFollow struct {
+	ID               uuid.UUID
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+	UserID           uuid.UUID
+	FeedID           uuid.UUID
+	FolderID         uuid.NullUUID
+	Title            sql.NullString
+	Muted            bool
+	Notifications    string
+	SortPriority     int32
+	HideFromTimeline bool
+}
+
+type UsersPostsState struct {
 	UserID    uuid.UUID
-	FeedID    uuid.UUID
+	PostID    uuid.UUID
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	ReadAt    sql.NullTime
+	StarredAt sql.NullTime
+	HiddenAt  sql.NullTime
 }
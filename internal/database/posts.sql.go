@@ -11,12 +11,13 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 const createPost = `-- name: CreatePost :one
-INSERT INTO posts(id, created_at, updated_at, title, url, description, published_at, feed_id)
-VALUES($1, $2, $3, $4, $5, $6, $7, $8)
-RETURNING id, created_at, updated_at, title, url, description, published_at, feed_id
+INSERT INTO posts(id, created_at, updated_at, title, url, description, published_at, feed_id, content, author, categories)
+VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+RETURNING id, created_at, updated_at, title, url, description, published_at, feed_id, content, author, categories
 `
 
 type CreatePostParams struct {
@@ -27,7 +28,10 @@ type CreatePostParams struct {
 	Url         string
 	Description sql.NullString
 	PublishedAt time.Time
-	FeedID      uuid.UUID
+	FeedID      uuid.NullUUID
+	Content     sql.NullString
+	Author      sql.NullString
+	Categories  []string
 }
 
 func (q *Queries) CreatePost(ctx context.Context, arg CreatePostParams) (Post, error) {
@@ -40,6 +44,9 @@ func (q *Queries) CreatePost(ctx context.Context, arg CreatePostParams) (Post, e
 		arg.Description,
 		arg.PublishedAt,
 		arg.FeedID,
+		arg.Content,
+		arg.Author,
+		pq.Array(arg.Categories),
 	)
 	var i Post
 	err := row.Scan(
@@ -51,25 +58,283 @@ func (q *Queries) CreatePost(ctx context.Context, arg CreatePostParams) (Post, e
 		&i.Description,
 		&i.PublishedAt,
 		&i.FeedID,
+		&i.Content,
+		&i.Author,
+		pq.Array(&i.Categories),
 	)
 	return i, err
 }
 
-const getPostByUser = `-- name: GetPostByUser :many
-SELECT posts.id, posts.created_at, posts.updated_at, posts.title, posts.url, posts.description, posts.published_at, posts.feed_id FROM posts
-JOIN feeds ON posts.feed_id = feeds.id
-WHERE feeds.user_id = $1
-ORDER BY posts.published_at DESC
+const getPostsForUser = `-- name: GetPostsForUser :many
+SELECT posts.id, posts.created_at, posts.updated_at, posts.title, posts.url, posts.description, posts.published_at, posts.feed_id, posts.content, posts.author, posts.categories FROM posts
+JOIN users_feeds_follows ON users_feeds_follows.feed_id = posts.feed_id
+WHERE users_feeds_follows.user_id = $1
+AND ($2::uuid[] IS NULL OR posts.feed_id = ANY($2::uuid[]))
+AND ($3::uuid IS NULL OR users_feeds_follows.folder_id = $3)
+AND ($4::uuid IS NULL OR EXISTS (
+    SELECT 1 FROM posts_labels
+    WHERE posts_labels.label_id = $4
+    AND posts_labels.post_id = posts.id
+))
+AND (NOT users_feeds_follows.hide_from_timeline OR $2 IS NOT NULL OR $3 IS NOT NULL OR $4 IS NOT NULL)
+AND ($5::timestamp IS NULL OR posts.published_at >= $5)
+AND ($6::timestamp IS NULL OR posts.published_at < $6)
+AND ($7::timestamp IS NULL OR (posts.published_at, posts.id) < ($7, $8::uuid))
+AND ($9::timestamp IS NULL OR (posts.published_at, posts.id) > ($9, $10::uuid))
+AND (NOT $11::boolean OR NOT EXISTS (
+    SELECT 1 FROM users_posts_states
+    WHERE users_posts_states.user_id = users_feeds_follows.user_id
+    AND users_posts_states.post_id = posts.id
+    AND users_posts_states.read_at IS NOT NULL
+))
+AND NOT EXISTS (
+    SELECT 1 FROM users_posts_states
+    WHERE users_posts_states.user_id = users_feeds_follows.user_id
+    AND users_posts_states.post_id = posts.id
+    AND users_posts_states.hidden_at IS NOT NULL
+)
+ORDER BY posts.published_at DESC, posts.id DESC
+LIMIT $12
+`
+
+type GetPostsForUserParams struct {
+	UserID            uuid.UUID
+	FeedIds           []uuid.UUID
+	FolderID          uuid.NullUUID
+	LabelID           uuid.NullUUID
+	Since             sql.NullTime
+	Until             sql.NullTime
+	BeforePublishedAt sql.NullTime
+	BeforeID          uuid.NullUUID
+	AfterPublishedAt  sql.NullTime
+	AfterID           uuid.NullUUID
+	UnreadOnly        bool
+	RowLimit          int32
+}
+
+func (q *Queries) GetPostsForUser(ctx context.Context, arg GetPostsForUserParams) ([]Post, error) {
+	rows, err := q.db.QueryContext(ctx, getPostsForUser,
+		arg.UserID,
+		pq.Array(arg.FeedIds),
+		arg.FolderID,
+		arg.LabelID,
+		arg.Since,
+		arg.Until,
+		arg.BeforePublishedAt,
+		arg.BeforeID,
+		arg.AfterPublishedAt,
+		arg.AfterID,
+		arg.UnreadOnly,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Post
+	for rows.Next() {
+		var i Post
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Title,
+			&i.Url,
+			&i.Description,
+			&i.PublishedAt,
+			&i.FeedID,
+			&i.Content,
+			&i.Author,
+			pq.Array(&i.Categories),
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPostsForUserOldestFirst = `-- name: GetPostsForUserOldestFirst :many
+SELECT posts.id, posts.created_at, posts.updated_at, posts.title, posts.url, posts.description, posts.published_at, posts.feed_id, posts.content, posts.author, posts.categories FROM posts
+JOIN users_feeds_follows ON users_feeds_follows.feed_id = posts.feed_id
+WHERE users_feeds_follows.user_id = $1
+AND ($2::uuid[] IS NULL OR posts.feed_id = ANY($2::uuid[]))
+AND ($3::uuid IS NULL OR users_feeds_follows.folder_id = $3)
+AND ($4::uuid IS NULL OR EXISTS (
+    SELECT 1 FROM posts_labels
+    WHERE posts_labels.label_id = $4
+    AND posts_labels.post_id = posts.id
+))
+AND (NOT users_feeds_follows.hide_from_timeline OR $2 IS NOT NULL OR $3 IS NOT NULL OR $4 IS NOT NULL)
+AND ($5::timestamp IS NULL OR posts.published_at >= $5)
+AND ($6::timestamp IS NULL OR posts.published_at < $6)
+AND ($7::timestamp IS NULL OR (posts.published_at, posts.id) < ($7, $8::uuid))
+AND ($9::timestamp IS NULL OR (posts.published_at, posts.id) > ($9, $10::uuid))
+AND (NOT $11::boolean OR NOT EXISTS (
+    SELECT 1 FROM users_posts_states
+    WHERE users_posts_states.user_id = users_feeds_follows.user_id
+    AND users_posts_states.post_id = posts.id
+    AND users_posts_states.read_at IS NOT NULL
+))
+AND NOT EXISTS (
+    SELECT 1 FROM users_posts_states
+    WHERE users_posts_states.user_id = users_feeds_follows.user_id
+    AND users_posts_states.post_id = posts.id
+    AND users_posts_states.hidden_at IS NOT NULL
+)
+ORDER BY posts.published_at ASC, posts.id ASC
+LIMIT $12
+`
+
+type GetPostsForUserOldestFirstParams struct {
+	UserID            uuid.UUID
+	FeedIds           []uuid.UUID
+	FolderID          uuid.NullUUID
+	LabelID           uuid.NullUUID
+	Since             sql.NullTime
+	Until             sql.NullTime
+	BeforePublishedAt sql.NullTime
+	BeforeID          uuid.NullUUID
+	AfterPublishedAt  sql.NullTime
+	AfterID           uuid.NullUUID
+	UnreadOnly        bool
+	RowLimit          int32
+}
+
+func (q *Queries) GetPostsForUserOldestFirst(ctx context.Context, arg GetPostsForUserOldestFirstParams) ([]Post, error) {
+	rows, err := q.db.QueryContext(ctx, getPostsForUserOldestFirst,
+		arg.UserID,
+		pq.Array(arg.FeedIds),
+		arg.FolderID,
+		arg.LabelID,
+		arg.Since,
+		arg.Until,
+		arg.BeforePublishedAt,
+		arg.BeforeID,
+		arg.AfterPublishedAt,
+		arg.AfterID,
+		arg.UnreadOnly,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Post
+	for rows.Next() {
+		var i Post
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Title,
+			&i.Url,
+			&i.Description,
+			&i.PublishedAt,
+			&i.FeedID,
+			&i.Content,
+			&i.Author,
+			pq.Array(&i.Categories),
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getRecentPostsForUser = `-- name: GetRecentPostsForUser :many
+SELECT posts.id, posts.created_at, posts.updated_at, posts.title, posts.url, posts.description, posts.published_at, posts.feed_id, posts.content, posts.author, posts.categories, feeds.name AS feed_name
+FROM posts
+JOIN feeds ON feeds.id = posts.feed_id
+JOIN users_feeds_follows ON users_feeds_follows.feed_id = posts.feed_id
+WHERE users_feeds_follows.user_id = $1
+ORDER BY posts.published_at DESC, posts.id DESC
 LIMIT $2
 `
 
-type GetPostByUserParams struct {
+type GetRecentPostsForUserParams struct {
 	UserID uuid.UUID
 	Limit  int32
 }
 
-func (q *Queries) GetPostByUser(ctx context.Context, arg GetPostByUserParams) ([]Post, error) {
-	rows, err := q.db.QueryContext(ctx, getPostByUser, arg.UserID, arg.Limit)
+type GetRecentPostsForUserRow struct {
+	Post     Post
+	FeedName string
+}
+
+func (q *Queries) GetRecentPostsForUser(ctx context.Context, arg GetRecentPostsForUserParams) ([]GetRecentPostsForUserRow, error) {
+	rows, err := q.db.QueryContext(ctx, getRecentPostsForUser, arg.UserID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetRecentPostsForUserRow
+	for rows.Next() {
+		var i GetRecentPostsForUserRow
+		if err := rows.Scan(
+			&i.Post.ID,
+			&i.Post.CreatedAt,
+			&i.Post.UpdatedAt,
+			&i.Post.Title,
+			&i.Post.Url,
+			&i.Post.Description,
+			&i.Post.PublishedAt,
+			&i.Post.FeedID,
+			&i.Post.Content,
+			&i.Post.Author,
+			pq.Array(&i.Post.Categories),
+			&i.FeedName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getStarredPostsForUser = `-- name: GetStarredPostsForUser :many
+SELECT posts.id, posts.created_at, posts.updated_at, posts.title, posts.url, posts.description, posts.published_at, posts.feed_id, posts.content, posts.author, posts.categories FROM posts
+JOIN users_posts_states ON users_posts_states.post_id = posts.id
+WHERE users_posts_states.user_id = $1
+AND users_posts_states.starred_at IS NOT NULL
+AND ($2::timestamp IS NULL OR (posts.published_at, posts.id) < ($2, $3::uuid))
+ORDER BY posts.published_at DESC, posts.id DESC
+LIMIT $4
+`
+
+type GetStarredPostsForUserParams struct {
+	UserID            uuid.UUID
+	BeforePublishedAt sql.NullTime
+	BeforeID          uuid.NullUUID
+	RowLimit          int32
+}
+
+func (q *Queries) GetStarredPostsForUser(ctx context.Context, arg GetStarredPostsForUserParams) ([]Post, error) {
+	rows, err := q.db.QueryContext(ctx, getStarredPostsForUser,
+		arg.UserID,
+		arg.BeforePublishedAt,
+		arg.BeforeID,
+		arg.RowLimit,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -86,6 +351,98 @@ func (q *Queries) GetPostByUser(ctx context.Context, arg GetPostByUserParams) ([
 			&i.Description,
 			&i.PublishedAt,
 			&i.FeedID,
+			&i.Content,
+			&i.Author,
+			pq.Array(&i.Categories),
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchPostsForUser = `-- name: SearchPostsForUser :many
+SELECT posts.id, posts.created_at, posts.updated_at, posts.title, posts.url, posts.description, posts.published_at, posts.feed_id, posts.content, posts.author, posts.categories,
+ts_rank_cd(to_tsvector('english', posts.title || ' ' || coalesce(posts.description, '') || ' ' || coalesce(posts.content, '')), tsq)::real AS rank,
+ts_headline('english', replace(replace(replace(replace(replace(regexp_replace(posts.title || ' ' || coalesce(posts.description, '') || ' ' || coalesce(posts.content, ''), '<[^>]*>', ' ', 'g'), '&', '&amp;'), '<', '&lt;'), '>', '&gt;'), '"', '&quot;'), '''', '&#39;'), tsq, 'StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MinWords=10, MaxWords=30')::text AS snippet
+FROM posts
+JOIN users_feeds_follows ON users_feeds_follows.feed_id = posts.feed_id
+LEFT JOIN users_posts_states ON users_posts_states.post_id = posts.id AND users_posts_states.user_id = users_feeds_follows.user_id
+CROSS JOIN to_tsquery('english', $1) AS tsq
+WHERE users_feeds_follows.user_id = $2
+AND to_tsvector('english', posts.title || ' ' || coalesce(posts.description, '') || ' ' || coalesce(posts.content, '')) @@ tsq
+AND ($3::uuid[] IS NULL OR posts.feed_id = ANY($3::uuid[]))
+AND ($4::uuid IS NULL OR users_feeds_follows.folder_id = $4)
+AND (NOT users_feeds_follows.hide_from_timeline OR $3 IS NOT NULL OR $4 IS NOT NULL)
+AND users_posts_states.hidden_at IS NULL
+AND ($5::timestamp IS NULL OR posts.published_at >= $5)
+AND ($6::timestamp IS NULL OR posts.published_at < $6)
+AND ($7::boolean IS NULL OR (users_posts_states.read_at IS NOT NULL) = $7)
+AND ($8::boolean IS NULL OR (users_posts_states.starred_at IS NOT NULL) = $8)
+ORDER BY rank DESC, posts.published_at DESC, posts.id DESC
+OFFSET $9 LIMIT $10
+`
+
+type SearchPostsForUserParams struct {
+	SearchQuery string
+	UserID      uuid.UUID
+	FeedIds     []uuid.UUID
+	FolderID    uuid.NullUUID
+	Since       sql.NullTime
+	Until       sql.NullTime
+	IsRead      sql.NullBool
+	IsStarred   sql.NullBool
+	RowOffset   int32
+	RowLimit    int32
+}
+
+type SearchPostsForUserRow struct {
+	Post    Post
+	Rank    float32
+	Snippet string
+}
+
+func (q *Queries) SearchPostsForUser(ctx context.Context, arg SearchPostsForUserParams) ([]SearchPostsForUserRow, error) {
+	rows, err := q.db.QueryContext(ctx, searchPostsForUser,
+		arg.SearchQuery,
+		arg.UserID,
+		pq.Array(arg.FeedIds),
+		arg.FolderID,
+		arg.Since,
+		arg.Until,
+		arg.IsRead,
+		arg.IsStarred,
+		arg.RowOffset,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchPostsForUserRow
+	for rows.Next() {
+		var i SearchPostsForUserRow
+		if err := rows.Scan(
+			&i.Post.ID,
+			&i.Post.CreatedAt,
+			&i.Post.UpdatedAt,
+			&i.Post.Title,
+			&i.Post.Url,
+			&i.Post.Description,
+			&i.Post.PublishedAt,
+			&i.Post.FeedID,
+			&i.Post.Content,
+			&i.Post.Author,
+			pq.Array(&i.Post.Categories),
+			&i.Rank,
+			&i.Snippet,
 		); err != nil {
 			return nil, err
 		}
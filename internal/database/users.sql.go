@@ -7,22 +7,24 @@ package database
 
 import (
 	"context"
+	"database/sql"
 	"time"
 
 	"github.com/google/uuid"
 )
 
 const createUser = `-- name: CreateUser :one
-INSERT INTO users(id, created_at, updated_at, name, api_key)
-VALUES($1, $2, $3, $4, DEFAULT)
-RETURNING id, created_at, updated_at, name, api_key
+INSERT INTO users(id, created_at, updated_at, name, password_hash)
+VALUES($1, $2, $3, $4, $5)
+RETURNING id, created_at, updated_at, name, role, password_hash, disabled_at
 `
 
 type CreateUserParams struct {
-	ID        uuid.UUID
-	CreatedAt time.Time
-	UpdatedAt time.Time
-	Name      string
+	ID           uuid.UUID
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	Name         string
+	PasswordHash sql.NullString
 }
 
 func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, error) {
@@ -31,6 +33,7 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		arg.CreatedAt,
 		arg.UpdatedAt,
 		arg.Name,
+		arg.PasswordHash,
 	)
 	var i User
 	err := row.Scan(
@@ -38,25 +41,75 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.Name,
-		&i.ApiKey,
+		&i.Role,
+		&i.PasswordHash,
+		&i.DisabledAt,
 	)
 	return i, err
 }
 
-const getUserByAPIKey = `-- name: GetUserByAPIKey :one
-SELECT id, created_at, updated_at, name, api_key FROM users
-WHERE api_key = $1
+const getUserByName = `-- name: GetUserByName :one
+SELECT id, created_at, updated_at, name, role, password_hash, disabled_at FROM users
+WHERE lower(name) = lower($1) AND password_hash IS NOT NULL
 `
 
-func (q *Queries) GetUserByAPIKey(ctx context.Context, apiKey string) (User, error) {
-	row := q.db.QueryRowContext(ctx, getUserByAPIKey, apiKey)
+func (q *Queries) GetUserByName(ctx context.Context, name string) (User, error) {
+	row := q.db.QueryRowContext(ctx, getUserByName, name)
 	var i User
 	err := row.Scan(
 		&i.ID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.Name,
-		&i.ApiKey,
+		&i.Role,
+		&i.PasswordHash,
+		&i.DisabledAt,
 	)
 	return i, err
 }
+
+const setUserPassword = `-- name: SetUserPassword :exec
+UPDATE users
+SET password_hash = $2, updated_at = NOW()
+WHERE id = $1
+`
+
+type SetUserPasswordParams struct {
+	ID           uuid.UUID
+	PasswordHash sql.NullString
+}
+
+func (q *Queries) SetUserPassword(ctx context.Context, arg SetUserPasswordParams) error {
+	_, err := q.db.ExecContext(ctx, setUserPassword, arg.ID, arg.PasswordHash)
+	return err
+}
+
+const setUserRole = `-- name: SetUserRole :exec
+UPDATE users
+SET role = $2, updated_at = NOW()
+WHERE id = $1 AND role <> $2
+`
+
+type SetUserRoleParams struct {
+	ID   uuid.UUID
+	Role string
+}
+
+func (q *Queries) SetUserRole(ctx context.Context, arg SetUserRoleParams) error {
+	_, err := q.db.ExecContext(ctx, setUserRole, arg.ID, arg.Role)
+	return err
+}
+
+const userNameExists = `-- name: UserNameExists :one
+SELECT EXISTS (
+    SELECT 1 FROM users
+    WHERE lower(name) = lower($1)
+)
+`
+
+func (q *Queries) UserNameExists(ctx context.Context, name string) (bool, error) {
+	row := q.db.QueryRowContext(ctx, userNameExists, name)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
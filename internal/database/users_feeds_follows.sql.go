@@ -7,6 +7,7 @@ package database
 
 import (
 	"context"
+	"database/sql"
 	"time"
 
 	"github.com/google/uuid"
@@ -15,7 +16,7 @@ import (
 const followFeed = `-- name: FollowFeed :one
 INSERT INTO users_feeds_follows(id, created_at, updated_at, user_id, feed_id)
 VALUES($1, $2, $3, $4, $5)
-RETURNING id, created_at, updated_at, user_id, feed_id
+RETURNING id, created_at, updated_at, user_id, feed_id, folder_id, title, muted, notifications, sort_priority, hide_from_timeline
 `
 
 type FollowFeedParams struct {
@@ -41,12 +42,18 @@ func (q *Queries) FollowFeed(ctx context.Context, arg FollowFeedParams) (UsersFe
 		&i.UpdatedAt,
 		&i.UserID,
 		&i.FeedID,
+		&i.FolderID,
+		&i.Title,
+		&i.Muted,
+		&i.Notifications,
+		&i.SortPriority,
+		&i.HideFromTimeline,
 	)
 	return i, err
 }
 
 const getFeedFollowByID = `-- name: GetFeedFollowByID :one
-SELECT id, created_at, updated_at, user_id, feed_id FROM users_feeds_follows
+SELECT id, created_at, updated_at, user_id, feed_id, folder_id, title, muted, notifications, sort_priority, hide_from_timeline FROM users_feeds_follows
 WHERE id = $1
 `
 
@@ -59,30 +66,80 @@ func (q *Queries) GetFeedFollowByID(ctx context.Context, id uuid.UUID) (UsersFee
 		&i.UpdatedAt,
 		&i.UserID,
 		&i.FeedID,
+		&i.FolderID,
+		&i.Title,
+		&i.Muted,
+		&i.Notifications,
+		&i.SortPriority,
+		&i.HideFromTimeline,
+	)
+	return i, err
+}
+
+const getFeedFollowByUserAndFeed = `-- name: GetFeedFollowByUserAndFeed :one
+SELECT id, created_at, updated_at, user_id, feed_id, folder_id, title, muted, notifications, sort_priority, hide_from_timeline FROM users_feeds_follows
+WHERE user_id = $1 AND feed_id = $2
+`
+
+type GetFeedFollowByUserAndFeedParams struct {
+	UserID uuid.UUID
+	FeedID uuid.UUID
+}
+
+func (q *Queries) GetFeedFollowByUserAndFeed(ctx context.Context, arg GetFeedFollowByUserAndFeedParams) (UsersFeedsFollow, error) {
+	row := q.db.QueryRowContext(ctx, getFeedFollowByUserAndFeed, arg.UserID, arg.FeedID)
+	var i UsersFeedsFollow
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.UserID,
+		&i.FeedID,
+		&i.FolderID,
+		&i.Title,
+		&i.Muted,
+		&i.Notifications,
+		&i.SortPriority,
+		&i.HideFromTimeline,
 	)
 	return i, err
 }
 
 const getFeedFollows = `-- name: GetFeedFollows :many
-SELECT id, created_at, updated_at, user_id, feed_id FROM users_feeds_follows
-WHERE user_id = $1
+SELECT users_feeds_follows.id, users_feeds_follows.created_at, users_feeds_follows.updated_at, users_feeds_follows.user_id, users_feeds_follows.feed_id, users_feeds_follows.folder_id, users_feeds_follows.title, users_feeds_follows.muted, users_feeds_follows.notifications, users_feeds_follows.sort_priority, users_feeds_follows.hide_from_timeline, feeds.name AS feed_name
+FROM users_feeds_follows
+JOIN feeds ON feeds.id = users_feeds_follows.feed_id
+WHERE users_feeds_follows.user_id = $1
+ORDER BY users_feeds_follows.sort_priority DESC, users_feeds_follows.created_at ASC
 `
 
-func (q *Queries) GetFeedFollows(ctx context.Context, userID uuid.UUID) ([]UsersFeedsFollow, error) {
+type GetFeedFollowsRow struct {
+	UsersFeedsFollow UsersFeedsFollow
+	FeedName         string
+}
+
+func (q *Queries) GetFeedFollows(ctx context.Context, userID uuid.UUID) ([]GetFeedFollowsRow, error) {
 	rows, err := q.db.QueryContext(ctx, getFeedFollows, userID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []UsersFeedsFollow
+	var items []GetFeedFollowsRow
 	for rows.Next() {
-		var i UsersFeedsFollow
+		var i GetFeedFollowsRow
 		if err := rows.Scan(
-			&i.ID,
-			&i.CreatedAt,
-			&i.UpdatedAt,
-			&i.UserID,
-			&i.FeedID,
+			&i.UsersFeedsFollow.ID,
+			&i.UsersFeedsFollow.CreatedAt,
+			&i.UsersFeedsFollow.UpdatedAt,
+			&i.UsersFeedsFollow.UserID,
+			&i.UsersFeedsFollow.FeedID,
+			&i.UsersFeedsFollow.FolderID,
+			&i.UsersFeedsFollow.Title,
+			&i.UsersFeedsFollow.Muted,
+			&i.UsersFeedsFollow.Notifications,
+			&i.UsersFeedsFollow.SortPriority,
+			&i.UsersFeedsFollow.HideFromTimeline,
+			&i.FeedName,
 		); err != nil {
 			return nil, err
 		}
@@ -97,6 +154,88 @@ func (q *Queries) GetFeedFollows(ctx context.Context, userID uuid.UUID) ([]Users
 	return items, nil
 }
 
+const getFeedFollowsForExport = `-- name: GetFeedFollowsForExport :many
+SELECT feeds.name AS feed_name, feeds.url, feeds.site_url, users_feeds_follows.title, folders.name AS folder_name
+FROM users_feeds_follows
+JOIN feeds ON feeds.id = users_feeds_follows.feed_id
+LEFT JOIN folders ON folders.id = users_feeds_follows.folder_id
+WHERE users_feeds_follows.user_id = $1
+ORDER BY folders.position ASC NULLS LAST, folders.name ASC, users_feeds_follows.sort_priority DESC, users_feeds_follows.created_at ASC
+`
+
+type GetFeedFollowsForExportRow struct {
+	FeedName   string
+	Url        string
+	SiteUrl    sql.NullString
+	Title      sql.NullString
+	FolderName sql.NullString
+}
+
+func (q *Queries) GetFeedFollowsForExport(ctx context.Context, userID uuid.UUID) ([]GetFeedFollowsForExportRow, error) {
+	rows, err := q.db.QueryContext(ctx, getFeedFollowsForExport, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetFeedFollowsForExportRow
+	for rows.Next() {
+		var i GetFeedFollowsForExportRow
+		if err := rows.Scan(
+			&i.FeedName,
+			&i.Url,
+			&i.SiteUrl,
+			&i.Title,
+			&i.FolderName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setFeedFollowFolder = `-- name: SetFeedFollowFolder :one
+UPDATE users_feeds_follows
+SET folder_id = $1, updated_at = NOW()
+WHERE users_feeds_follows.id = $2 AND users_feeds_follows.user_id = $3
+AND ($1::uuid IS NULL OR EXISTS (
+    SELECT 1 FROM folders
+    WHERE folders.id = $1 AND folders.user_id = $3
+))
+RETURNING id, created_at, updated_at, user_id, feed_id, folder_id, title, muted, notifications, sort_priority, hide_from_timeline
+`
+
+type SetFeedFollowFolderParams struct {
+	FolderID uuid.NullUUID
+	ID       uuid.UUID
+	UserID   uuid.UUID
+}
+
+func (q *Queries) SetFeedFollowFolder(ctx context.Context, arg SetFeedFollowFolderParams) (UsersFeedsFollow, error) {
+	row := q.db.QueryRowContext(ctx, setFeedFollowFolder, arg.FolderID, arg.ID, arg.UserID)
+	var i UsersFeedsFollow
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.UserID,
+		&i.FeedID,
+		&i.FolderID,
+		&i.Title,
+		&i.Muted,
+		&i.Notifications,
+		&i.SortPriority,
+		&i.HideFromTimeline,
+	)
+	return i, err
+}
+
 const unfollowFeed = `-- name: UnfollowFeed :exec
 DELETE FROM users_feeds_follows
 WHERE id = $1 AND user_id = $2
@@ -111,3 +250,52 @@ func (q *Queries) UnfollowFeed(ctx context.Context, arg UnfollowFeedParams) erro
 	_, err := q.db.ExecContext(ctx, unfollowFeed, arg.ID, arg.UserID)
 	return err
 }
+
+const updateFeedFollowSettings = `-- name: UpdateFeedFollowSettings :one
+UPDATE users_feeds_follows
+SET title = CASE WHEN $1::text IS NULL THEN title ELSE NULLIF($1, '') END,
+muted = COALESCE($2, muted),
+notifications = COALESCE($3, notifications),
+sort_priority = COALESCE($4, sort_priority),
+hide_from_timeline = COALESCE($5, hide_from_timeline),
+updated_at = NOW()
+WHERE id = $6 AND user_id = $7
+RETURNING id, created_at, updated_at, user_id, feed_id, folder_id, title, muted, notifications, sort_priority, hide_from_timeline
+`
+
+type UpdateFeedFollowSettingsParams struct {
+	Title            sql.NullString
+	Muted            sql.NullBool
+	Notifications    sql.NullString
+	SortPriority     sql.NullInt32
+	HideFromTimeline sql.NullBool
+	ID               uuid.UUID
+	UserID           uuid.UUID
+}
+
+func (q *Queries) UpdateFeedFollowSettings(ctx context.Context, arg UpdateFeedFollowSettingsParams) (UsersFeedsFollow, error) {
+	row := q.db.QueryRowContext(ctx, updateFeedFollowSettings,
+		arg.Title,
+		arg.Muted,
+		arg.Notifications,
+		arg.SortPriority,
+		arg.HideFromTimeline,
+		arg.ID,
+		arg.UserID,
+	)
+	var i UsersFeedsFollow
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.UserID,
+		&i.FeedID,
+		&i.FolderID,
+		&i.Title,
+		&i.Muted,
+		&i.Notifications,
+		&i.SortPriority,
+		&i.HideFromTimeline,
+	)
+	return i, err
+}
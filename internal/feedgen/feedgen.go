@@ -0,0 +1,238 @@
+package feedgen
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"time"
+)
+
+// Feed is a format independent description of a generated feed.
+type Feed struct {
+	ID          string
+	Title       string
+	Description string
+	Link        string
+	FeedURL     string
+	Author      string
+	Updated     time.Time
+	Items       []Item
+}
+
+type Item struct {
+	ID          string
+	Title       string
+	Link        string
+	Description string
+	Published   time.Time
+	Updated     time.Time
+}
+
+type rssDocument struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Atom    string     `xml:"xmlns:atom,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	AtomLink      rssLink   `xml:"atom:link"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+	Type string `xml:"type,attr"`
+}
+
+type rssItem struct {
+	Title       string  `xml:"title"`
+	Link        string  `xml:"link"`
+	GUID        rssGUID `xml:"guid"`
+	Description string  `xml:"description,omitempty"`
+	PubDate     string  `xml:"pubDate"`
+}
+
+type rssGUID struct {
+	Value       string `xml:",chardata"`
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+}
+
+// WriteRSS renders feed as an RSS 2.0 document.
+func WriteRSS(w io.Writer, feed Feed) error {
+	doc := rssDocument{
+		Version: "2.0",
+		Atom:    "http://www.w3.org/2005/Atom",
+		Channel: rssChannel{
+			Title:       feed.Title,
+			Link:        feed.Link,
+			Description: feed.Description,
+			AtomLink: rssLink{
+				Href: feed.FeedURL,
+				Rel:  "self",
+				Type: "application/rss+xml",
+			},
+			LastBuildDate: feed.Updated.UTC().Format(time.RFC1123Z),
+			Items:         make([]rssItem, len(feed.Items)),
+		},
+	}
+
+	for i, v := range feed.Items {
+		doc.Channel.Items[i] = rssItem{
+			Title:       v.Title,
+			Link:        v.Link,
+			GUID:        rssGUID{Value: v.ID},
+			Description: v.Description,
+			PubDate:     v.Published.UTC().Format(time.RFC1123Z),
+		}
+	}
+
+	return writeXML(w, doc)
+}
+
+type atomDocument struct {
+	XMLName xml.Name    `xml:"feed"`
+	Xmlns   string      `xml:"xmlns,attr"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Author  atomAuthor  `xml:"author"`
+	Links   []atomLink  `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+}
+
+type atomText struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+type atomEntry struct {
+	ID        string    `xml:"id"`
+	Title     string    `xml:"title"`
+	Link      atomLink  `xml:"link"`
+	Published string    `xml:"published"`
+	Updated   string    `xml:"updated"`
+	Summary   *atomText `xml:"summary"`
+}
+
+// WriteAtom renders feed as an Atom 1.0 document.
+func WriteAtom(w io.Writer, feed Feed) error {
+	doc := atomDocument{
+		Xmlns:   "http://www.w3.org/2005/Atom",
+		ID:      feed.ID,
+		Title:   feed.Title,
+		Updated: feed.Updated.UTC().Format(time.RFC3339),
+		Author:  atomAuthor{Name: feed.Author},
+		Links: []atomLink{
+			{Href: feed.FeedURL, Rel: "self"},
+		},
+		Entries: make([]atomEntry, len(feed.Items)),
+	}
+	if feed.Link != "" {
+		doc.Links = append(doc.Links, atomLink{Href: feed.Link, Rel: "alternate"})
+	}
+
+	for i, v := range feed.Items {
+		doc.Entries[i] = atomEntry{
+			ID:        v.ID,
+			Title:     v.Title,
+			Link:      atomLink{Href: v.Link, Rel: "alternate"},
+			Published: v.Published.UTC().Format(time.RFC3339),
+			Updated:   latest(v.Updated, v.Published).UTC().Format(time.RFC3339),
+		}
+		if v.Description != "" {
+			doc.Entries[i].Summary = &atomText{Type: "html", Value: v.Description}
+		}
+	}
+
+	return writeXML(w, doc)
+}
+
+type jsonFeedDocument struct {
+	Version     string         `json:"version"`
+	Title       string         `json:"title"`
+	HomePageURL string         `json:"home_page_url,omitempty"`
+	FeedURL     string         `json:"feed_url"`
+	Description string         `json:"description,omitempty"`
+	Authors     []jsonAuthor   `json:"authors,omitempty"`
+	Items       []jsonFeedItem `json:"items"`
+}
+
+type jsonAuthor struct {
+	Name string `json:"name"`
+}
+
+type jsonFeedItem struct {
+	ID            string `json:"id"`
+	URL           string `json:"url,omitempty"`
+	Title         string `json:"title"`
+	ContentHTML   string `json:"content_html"`
+	DatePublished string `json:"date_published"`
+	DateModified  string `json:"date_modified,omitempty"`
+}
+
+// WriteJSONFeed renders feed as a JSON Feed 1.1 document.
+func WriteJSONFeed(w io.Writer, feed Feed) error {
+	doc := jsonFeedDocument{
+		Version:     "https://jsonfeed.org/version/1.1",
+		Title:       feed.Title,
+		HomePageURL: feed.Link,
+		FeedURL:     feed.FeedURL,
+		Description: feed.Description,
+		Items:       make([]jsonFeedItem, len(feed.Items)),
+	}
+	if feed.Author != "" {
+		doc.Authors = []jsonAuthor{{Name: feed.Author}}
+	}
+
+	for i, v := range feed.Items {
+		doc.Items[i] = jsonFeedItem{
+			ID:            v.ID,
+			URL:           v.Link,
+			Title:         v.Title,
+			ContentHTML:   v.Description,
+			DatePublished: v.Published.UTC().Format(time.RFC3339),
+		}
+		if !v.Updated.IsZero() {
+			doc.Items[i].DateModified = v.Updated.UTC().Format(time.RFC3339)
+		}
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(doc)
+}
+
+func writeXML(w io.Writer, v interface{}) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+func latest(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,121 @@
+package feedgen
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"testing"
+	"time"
+)
+
+var testFeed = Feed{
+	ID:          "urn:uuid:3f1c1d0e-7a43-4f7e-9a57-2a6f0f7c1b11",
+	Title:       "Sample Timeline",
+	Description: "Posts from followed feeds",
+	Link:        "https://example.com",
+	FeedURL:     "https://example.com/v1/output/token/rss",
+	Author:      "Sample User",
+	Updated:     time.Date(2024, 7, 2, 8, 0, 0, 0, time.UTC),
+	Items: []Item{
+		{
+			ID:          "urn:uuid:9b2d6a5e-1f0c-4c8e-8d7a-5b1e2c3d4f50",
+			Title:       "First <post>",
+			Link:        "https://example.com/first",
+			Description: "<p>Hello & welcome</p>",
+			Published:   time.Date(2024, 7, 1, 12, 0, 0, 0, time.UTC),
+		},
+	},
+}
+
+func TestWriteRSS(t *testing.T) {
+	buf := new(bytes.Buffer)
+	if err := WriteRSS(buf, testFeed); err != nil {
+		t.Fatalf("Failed to write RSS: %v", err)
+	}
+
+	actual := struct {
+		Version string `xml:"version,attr"`
+		Channel struct {
+			Title string `xml:"title"`
+			Item  []struct {
+				Title       string `xml:"title"`
+				Link        string `xml:"link"`
+				GUID        string `xml:"guid"`
+				Description string `xml:"description"`
+				PubDate     string `xml:"pubDate"`
+			} `xml:"item"`
+		} `xml:"channel"`
+	}{}
+	if err := xml.Unmarshal(buf.Bytes(), &actual); err != nil {
+		t.Fatalf("Failed to parse RSS: %v", err)
+	}
+
+	if actual.Version != "2.0" {
+		t.Errorf("Expected version 2.0, got %v", actual.Version)
+	}
+	if len(actual.Channel.Item) != 1 {
+		t.Fatalf("Expected 1 item, got %v", len(actual.Channel.Item))
+	}
+	item := actual.Channel.Item[0]
+	if item.Title != testFeed.Items[0].Title || item.Description != testFeed.Items[0].Description {
+		t.Errorf("Expected item %v, got %v", testFeed.Items[0], item)
+	}
+	if item.PubDate != "Mon, 01 Jul 2024 12:00:00 +0000" {
+		t.Errorf("Expected RFC 1123 pubDate, got %v", item.PubDate)
+	}
+}
+
+func TestWriteAtom(t *testing.T) {
+	buf := new(bytes.Buffer)
+	if err := WriteAtom(buf, testFeed); err != nil {
+		t.Fatalf("Failed to write Atom: %v", err)
+	}
+
+	actual := struct {
+		XMLName xml.Name
+		ID      string `xml:"id"`
+		Updated string `xml:"updated"`
+		Entry   []struct {
+			ID      string `xml:"id"`
+			Updated string `xml:"updated"`
+			Summary string `xml:"summary"`
+		} `xml:"entry"`
+	}{}
+	if err := xml.Unmarshal(buf.Bytes(), &actual); err != nil {
+		t.Fatalf("Failed to parse Atom: %v", err)
+	}
+
+	if actual.XMLName.Space != "http://www.w3.org/2005/Atom" || actual.XMLName.Local != "feed" {
+		t.Errorf("Expected Atom feed element, got %v", actual.XMLName)
+	}
+	if actual.Updated != "2024-07-02T08:00:00Z" {
+		t.Errorf("Expected updated 2024-07-02T08:00:00Z, got %v", actual.Updated)
+	}
+	if len(actual.Entry) != 1 || actual.Entry[0].Updated != "2024-07-01T12:00:00Z" {
+		t.Errorf("Expected one entry updated at its publish date, got %v", actual.Entry)
+	}
+}
+
+func TestWriteJSONFeed(t *testing.T) {
+	buf := new(bytes.Buffer)
+	if err := WriteJSONFeed(buf, testFeed); err != nil {
+		t.Fatalf("Failed to write JSON Feed: %v", err)
+	}
+
+	actual := map[string]interface{}{}
+	if err := json.Unmarshal(buf.Bytes(), &actual); err != nil {
+		t.Fatalf("Failed to parse JSON Feed: %v", err)
+	}
+
+	if actual["version"] != "https://jsonfeed.org/version/1.1" {
+		t.Errorf("Expected JSON Feed 1.1, got %v", actual["version"])
+	}
+	items, ok := actual["items"].([]interface{})
+	if !ok || len(items) != 1 {
+		t.Fatalf("Expected 1 item, got %v", actual["items"])
+	}
+	item := items[0].(map[string]interface{})
+	if item["content_html"] != testFeed.Items[0].Description {
+		t.Errorf("Expected content_html %v, got %v", testFeed.Items[0].Description, item["content_html"])
+	}
+}
@@ -0,0 +1,96 @@
+package feedurl
+
+import (
+	"errors"
+	"net"
+	"net/url"
+	"strings"
+)
+
+var ErrInvalidURL = errors.New("Invalid feed URL")
+
+var trackingParams = map[string]bool{
+	"fbclid":  true,
+	"gclid":   true,
+	"dclid":   true,
+	"msclkid": true,
+	"mc_cid":  true,
+	"mc_eid":  true,
+	"igshid":  true,
+	"_ga":     true,
+}
+
+var defaultPorts = map[string]string{
+	"http":  "80",
+	"https": "443",
+}
+
+// Normalize cleans up a user supplied feed URL so that it can be fetched and
+// stored: the scheme defaults to https, scheme and host are lowercased,
+// default ports, fragments and tracking query parameters are dropped and the
+// remaining query parameters are sorted.
+func Normalize(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return "", ErrInvalidURL
+	}
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", ErrInvalidURL
+	}
+
+	u.Scheme = strings.ToLower(u.Scheme)
+	if _, ok := defaultPorts[u.Scheme]; !ok {
+		return "", ErrInvalidURL
+	}
+
+	host := strings.ToLower(u.Hostname())
+	if host == "" {
+		return "", ErrInvalidURL
+	}
+	if port := u.Port(); port != "" && port != defaultPorts[u.Scheme] {
+		host = net.JoinHostPort(host, port)
+	} else if strings.Contains(host, ":") {
+		host = "[" + host + "]"
+	}
+	u.Host = host
+
+	u.Fragment = ""
+	u.RawFragment = ""
+
+	query := u.Query()
+	for key := range query {
+		if isTrackingParam(key) {
+			query.Del(key)
+		}
+	}
+	u.RawQuery = query.Encode()
+	u.ForceQuery = false
+
+	return u.String(), nil
+}
+
+// Canonical returns the key used to detect duplicate feeds. It expects a URL
+// returned by Normalize and additionally ignores the scheme and any trailing
+// slash, so http://x.com/feed and https://x.com/feed/ share the same key.
+func Canonical(normalized string) string {
+	u, err := url.Parse(normalized)
+	if err != nil {
+		return normalized
+	}
+
+	key := u.Host + strings.TrimRight(u.EscapedPath(), "/")
+	if u.RawQuery != "" {
+		key += "?" + u.RawQuery
+	}
+	return key
+}
+
+func isTrackingParam(key string) bool {
+	key = strings.ToLower(key)
+	return strings.HasPrefix(key, "utm_") || trackingParams[key]
+}
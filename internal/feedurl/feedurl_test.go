@@ -0,0 +1,69 @@
+package feedurl
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		raw      string
+		expected string
+	}{
+		{"www.url.com", "https://www.url.com"},
+		{"HTTP://Example.COM/Feed", "http://example.com/Feed"},
+		{"https://example.com:443/feed/", "https://example.com/feed/"},
+		{"http://example.com:80/feed", "http://example.com/feed"},
+		{"http://example.com:8080/feed", "http://example.com:8080/feed"},
+		{"https://example.com/feed?utm_source=a&utm_medium=b", "https://example.com/feed"},
+		{"https://example.com/feed?b=2&fbclid=x&a=1#top", "https://example.com/feed?a=1&b=2"},
+		{"  https://example.com/feed  ", "https://example.com/feed"},
+	}
+
+	for _, test := range tests {
+		actual, err := Normalize(test.raw)
+		if err != nil {
+			t.Errorf("Normalize(%q) returned error: %v", test.raw, err)
+			continue
+		}
+		if actual != test.expected {
+			t.Errorf("Normalize(%q): expected %v, got %v", test.raw, test.expected, actual)
+		}
+	}
+}
+
+func TestNormalizeInvalid(t *testing.T) {
+	for _, raw := range []string{"", "   ", "ftp://example.com/feed", "https://", "https://exa mple.com/%zz"} {
+		if _, err := Normalize(raw); !errors.Is(err, ErrInvalidURL) {
+			t.Errorf("Normalize(%q): expected ErrInvalidURL, got %v", raw, err)
+		}
+	}
+}
+
+func TestCanonicalMatchesEquivalentURLs(t *testing.T) {
+	raws := []string{
+		"http://x.com/feed",
+		"https://x.com/feed/",
+		"https://X.com/feed?utm_source=a",
+		"x.com:443/feed",
+	}
+
+	expected := ""
+	for _, raw := range raws {
+		normalized, err := Normalize(raw)
+		if err != nil {
+			t.Fatalf("Normalize(%q) returned error: %v", raw, err)
+		}
+		actual := Canonical(normalized)
+		if expected == "" {
+			expected = actual
+		}
+		if actual != expected {
+			t.Errorf("Canonical(%q): expected %v, got %v", normalized, expected, actual)
+		}
+	}
+
+	if expected != "x.com/feed" {
+		t.Errorf("Expected canonical key x.com/feed, got %v", expected)
+	}
+}
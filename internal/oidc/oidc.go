@@ -0,0 +1,397 @@
+// Package oidc implements the parts of OpenID Connect needed to log users in
+// through an identity provider: discovery, the authorization code flow with
+// PKCE, and validation of RS256 signed ID tokens.
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	ErrInvalidToken = errors.New("Invalid ID token")
+	ErrExchange     = errors.New("Failed to exchange authorization code")
+)
+
+// clockSkew is how far the clocks of the provider and ours may disagree.
+const clockSkew = time.Minute
+
+// keyRefreshInterval limits how often tokens with unknown key IDs can make
+// us fetch the key set again.
+var keyRefreshInterval = time.Minute
+
+// Config describes the client registered with the identity provider.
+type Config struct {
+	IssuerURL    string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	// Scopes are requested besides "openid".
+	Scopes []string
+	// HTTPClient defaults to a client with a 10 second timeout.
+	HTTPClient *http.Client
+}
+
+type metadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// Provider is an identity provider found through discovery.
+type Provider struct {
+	config   Config
+	client   *http.Client
+	metadata metadata
+
+	mu            sync.Mutex
+	keys          map[string]*rsa.PublicKey
+	keysFetchedAt time.Time
+}
+
+// Discover reads the provider metadata published under the issuer URL.
+func Discover(ctx context.Context, config Config) (*Provider, error) {
+	client := config.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	issuer := strings.TrimSuffix(config.IssuerURL, "/")
+	m := metadata{}
+	if err := getJSON(ctx, client, issuer+"/.well-known/openid-configuration", &m); err != nil {
+		return nil, fmt.Errorf("Failed to discover provider: %w", err)
+	}
+	// The issuer must match exactly, or tokens of one tenant would pass for
+	// another's.
+	if strings.TrimSuffix(m.Issuer, "/") != issuer {
+		return nil, fmt.Errorf("Provider issuer %q doesn't match %q", m.Issuer, config.IssuerURL)
+	}
+	if m.AuthorizationEndpoint == "" || m.TokenEndpoint == "" || m.JWKSURI == "" {
+		return nil, errors.New("Provider metadata is incomplete")
+	}
+
+	return &Provider{
+		config:   config,
+		client:   client,
+		metadata: m,
+	}, nil
+}
+
+// Issuer returns the issuer identifier of the provider.
+func (p *Provider) Issuer() string {
+	return p.metadata.Issuer
+}
+
+// GenerateVerifier returns a random value for state, nonce or the PKCE code
+// verifier.
+func GenerateVerifier() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// AuthCodeURL returns the URL to send the user to for logging in.
+func (p *Provider) AuthCodeURL(state, nonce, verifier string) string {
+	challenge := sha256.Sum256([]byte(verifier))
+	values := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {p.config.ClientID},
+		"redirect_uri":          {p.config.RedirectURL},
+		"scope":                 {strings.Join(append([]string{"openid"}, p.config.Scopes...), " ")},
+		"state":                 {state},
+		"nonce":                 {nonce},
+		"code_challenge":        {base64.RawURLEncoding.EncodeToString(challenge[:])},
+		"code_challenge_method": {"S256"},
+	}
+
+	sep := "?"
+	if strings.Contains(p.metadata.AuthorizationEndpoint, "?") {
+		sep = "&"
+	}
+	return p.metadata.AuthorizationEndpoint + sep + values.Encode()
+}
+
+// Exchange redeems an authorization code and returns the claims of the
+// validated ID token that comes with it.
+func (p *Provider) Exchange(ctx context.Context, code, verifier, nonce string) (*Claims, error) {
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {p.config.RedirectURL},
+		"code_verifier": {verifier},
+		"client_id":     {p.config.ClientID},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.metadata.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if p.config.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(p.config.ClientID), url.QueryEscape(p.config.ClientSecret))
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrExchange, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrExchange, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: provider responded with %v", ErrExchange, resp.Status)
+	}
+
+	token := struct {
+		IDToken string `json:"id_token"`
+	}{}
+	if err := json.Unmarshal(body, &token); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrExchange, err)
+	}
+	if token.IDToken == "" {
+		return nil, fmt.Errorf("%w: no id_token in response", ErrExchange)
+	}
+
+	return p.Verify(ctx, token.IDToken, nonce)
+}
+
+// Verify checks the signature and claims of an ID token issued to us.
+func (p *Provider) Verify(ctx context.Context, rawToken, nonce string) (*Claims, error) {
+	parts := strings.Split(rawToken, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("%w: malformed token", ErrInvalidToken)
+	}
+
+	header := struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}{}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+	// Only RS256 is accepted; in particular never "none" or an HMAC keyed
+	// with a public key.
+	if header.Alg != "RS256" {
+		return nil, fmt.Errorf("%w: unsupported algorithm %q", ErrInvalidToken, header.Alg)
+	}
+
+	key, err := p.key(ctx, header.Kid)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature); err != nil {
+		return nil, fmt.Errorf("%w: bad signature", ErrInvalidToken)
+	}
+
+	claims := &Claims{}
+	if err := decodeSegment(parts[1], &claims.raw); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+	if err := decodeSegment(parts[1], claims); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+	if err := p.validate(claims, nonce); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+	return claims, nil
+}
+
+func (p *Provider) validate(claims *Claims, nonce string) error {
+	now := time.Now()
+	if claims.Issuer != p.metadata.Issuer {
+		return fmt.Errorf("unexpected issuer %q", claims.Issuer)
+	}
+	if !slices.Contains(claims.Audience, p.config.ClientID) {
+		return errors.New("token wasn't issued to this client")
+	}
+	if len(claims.Audience) > 1 && claims.AuthorizedParty != p.config.ClientID {
+		return errors.New("token wasn't issued to this client")
+	}
+	if claims.Subject == "" {
+		return errors.New("missing subject")
+	}
+	if claims.Expiry == 0 || now.After(time.Unix(claims.Expiry, 0).Add(clockSkew)) {
+		return errors.New("token expired")
+	}
+	if claims.IssuedAt != 0 && time.Unix(claims.IssuedAt, 0).After(now.Add(clockSkew)) {
+		return errors.New("token issued in the future")
+	}
+	if claims.Nonce != nonce {
+		return errors.New("nonce mismatch")
+	}
+	return nil
+}
+
+// key returns the signing key kid, fetching the key set again when it's
+// unknown so that rotated keys are picked up.
+func (p *Provider) key(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if key := lookupKey(p.keys, kid); key != nil {
+		return key, nil
+	}
+
+	if time.Since(p.keysFetchedAt) >= keyRefreshInterval {
+		keys, err := p.fetchKeys(ctx)
+		if err != nil {
+			return nil, err
+		}
+		p.keys = keys
+		p.keysFetchedAt = time.Now()
+	}
+
+	if key := lookupKey(p.keys, kid); key != nil {
+		return key, nil
+	}
+	return nil, fmt.Errorf("%w: unknown signing key %q", ErrInvalidToken, kid)
+}
+
+// lookupKey finds kid in keys. Tokens without a kid are only accepted when
+// the provider publishes a single key.
+func lookupKey(keys map[string]*rsa.PublicKey, kid string) *rsa.PublicKey {
+	if kid == "" && len(keys) == 1 {
+		for _, key := range keys {
+			return key
+		}
+	}
+	return keys[kid]
+}
+
+func (p *Provider) fetchKeys(ctx context.Context) (map[string]*rsa.PublicKey, error) {
+	set := struct {
+		Keys []struct {
+			Kty string `json:"kty"`
+			Kid string `json:"kid"`
+			Use string `json:"use"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+		} `json:"keys"`
+	}{}
+	if err := getJSON(ctx, p.client, p.metadata.JWKSURI, &set); err != nil {
+		return nil, fmt.Errorf("Failed to fetch signing keys: %w", err)
+	}
+
+	keys := make(map[string]*rsa.PublicKey, len(set.Keys))
+	for _, v := range set.Keys {
+		if v.Kty != "RSA" || (v.Use != "" && v.Use != "sig") {
+			continue
+		}
+		n, err := base64.RawURLEncoding.DecodeString(v.N)
+		if err != nil {
+			continue
+		}
+		e, err := base64.RawURLEncoding.DecodeString(v.E)
+		if err != nil || len(e) > 4 {
+			continue
+		}
+		keys[v.Kid] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+	return keys, nil
+}
+
+// Claims are the claims of a validated ID token.
+type Claims struct {
+	Issuer            string   `json:"iss"`
+	Subject           string   `json:"sub"`
+	Audience          audience `json:"aud"`
+	AuthorizedParty   string   `json:"azp"`
+	Expiry            int64    `json:"exp"`
+	IssuedAt          int64    `json:"iat"`
+	Nonce             string   `json:"nonce"`
+	Email             string   `json:"email"`
+	EmailVerified     bool     `json:"email_verified"`
+	Name              string   `json:"name"`
+	PreferredUsername string   `json:"preferred_username"`
+
+	raw map[string]any
+}
+
+// HasValue reports whether claim equals value, or is a list containing it.
+// Providers put group and role memberships in claims of either shape.
+func (c *Claims) HasValue(claim, value string) bool {
+	switch v := c.raw[claim].(type) {
+	case string:
+		return v == value
+	case []any:
+		for _, item := range v {
+			if s, ok := item.(string); ok && s == value {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// audience accepts both forms of the aud claim: a string or a list.
+type audience []string
+
+func (a *audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = audience{single}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	*a = list
+	return nil
+}
+
+func decodeSegment(segment string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+func getJSON(ctx context.Context, client *http.Client, url string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%v responded with %v", url, resp.Status)
+	}
+	return json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(v)
+}
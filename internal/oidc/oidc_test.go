@@ -0,0 +1,258 @@
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	testClientID     = "rss-aggregator"
+	testClientSecret = "secret"
+	testRedirectURL  = "https://rss.example.com/v1/oidc/callback"
+)
+
+// mockProvider is a minimal identity provider. It issues an ID token with
+// claims for any code whose PKCE verifier matches the challenge it was
+// requested with.
+type mockProvider struct {
+	t      *testing.T
+	server *httptest.Server
+	key    *rsa.PrivateKey
+	kid    string
+
+	// issuer overrides the issuer advertised in the metadata.
+	issuer    string
+	challenge string
+	claims    map[string]any
+}
+
+func newMockProvider(t *testing.T) *mockProvider {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	m := &mockProvider{t: t, key: key, kid: "key-1"}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		issuer := m.server.URL
+		if m.issuer != "" {
+			issuer = m.issuer
+		}
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 issuer,
+			"authorization_endpoint": m.server.URL + "/authorize",
+			"token_endpoint":         m.server.URL + "/token",
+			"jwks_uri":               m.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("GET /jwks", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]any{
+			"keys": []map[string]string{{
+				"kty": "RSA",
+				"use": "sig",
+				"kid": m.kid,
+				"n":   base64.RawURLEncoding.EncodeToString(m.key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(m.key.E)).Bytes()),
+			}},
+		})
+	})
+	mux.HandleFunc("POST /token", func(w http.ResponseWriter, r *http.Request) {
+		if id, secret, ok := r.BasicAuth(); !ok || id != testClientID || secret != testClientSecret {
+			http.Error(w, "invalid_client", http.StatusUnauthorized)
+			return
+		}
+		sum := sha256.Sum256([]byte(r.PostFormValue("code_verifier")))
+		if r.PostFormValue("code") != "code" || base64.RawURLEncoding.EncodeToString(sum[:]) != m.challenge {
+			http.Error(w, "invalid_grant", http.StatusBadRequest)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{
+			"access_token": "access",
+			"token_type":   "Bearer",
+			"id_token":     m.sign(m.claims),
+		})
+	})
+	m.server = httptest.NewServer(mux)
+	t.Cleanup(m.server.Close)
+
+	return m
+}
+
+func (m *mockProvider) validClaims(nonce string) map[string]any {
+	return map[string]any{
+		"iss":                m.server.URL,
+		"sub":                "user-1",
+		"aud":                testClientID,
+		"exp":                time.Now().Add(time.Hour).Unix(),
+		"iat":                time.Now().Unix(),
+		"nonce":              nonce,
+		"preferred_username": "alice",
+		"groups":             []string{"staff", "rss-admins"},
+	}
+}
+
+func (m *mockProvider) sign(claims map[string]any) string {
+	m.t.Helper()
+
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT", "kid": m.kid})
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		m.t.Fatalf("Marshal: %v", err)
+	}
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(signed))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, m.key, crypto.SHA256, digest[:])
+	if err != nil {
+		m.t.Fatalf("SignPKCS1v15: %v", err)
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func (m *mockProvider) discover(t *testing.T) *Provider {
+	t.Helper()
+
+	provider, err := Discover(context.Background(), Config{
+		IssuerURL:    m.server.URL,
+		ClientID:     testClientID,
+		ClientSecret: testClientSecret,
+		RedirectURL:  testRedirectURL,
+		Scopes:       []string{"profile", "email"},
+	})
+	if err != nil {
+		t.Fatalf("Discover: %v", err)
+	}
+	return provider
+}
+
+func TestAuthorizationCodeFlow(t *testing.T) {
+	m := newMockProvider(t)
+	provider := m.discover(t)
+
+	verifier, _ := GenerateVerifier()
+	authURL, err := url.Parse(provider.AuthCodeURL("state", "nonce", verifier))
+	if err != nil {
+		t.Fatalf("AuthCodeURL: %v", err)
+	}
+	query := authURL.Query()
+	if authURL.Path != "/authorize" || query.Get("client_id") != testClientID || query.Get("redirect_uri") != testRedirectURL {
+		t.Errorf("AuthCodeURL = %v", authURL)
+	}
+	if query.Get("scope") != "openid profile email" || query.Get("state") != "state" || query.Get("nonce") != "nonce" {
+		t.Errorf("AuthCodeURL = %v", authURL)
+	}
+	if query.Get("code_challenge_method") != "S256" {
+		t.Errorf("code_challenge_method = %q, want S256", query.Get("code_challenge_method"))
+	}
+	m.challenge = query.Get("code_challenge")
+	m.claims = m.validClaims("nonce")
+
+	claims, err := provider.Exchange(context.Background(), "code", verifier, "nonce")
+	if err != nil {
+		t.Fatalf("Exchange: %v", err)
+	}
+	if claims.Subject != "user-1" || claims.PreferredUsername != "alice" {
+		t.Errorf("claims = %+v", claims)
+	}
+	if !claims.HasValue("groups", "rss-admins") || claims.HasValue("groups", "root") {
+		t.Errorf("HasValue gave wrong results for groups %v", claims.raw["groups"])
+	}
+
+	if _, err := provider.Exchange(context.Background(), "code", "wrong verifier", "nonce"); !errors.Is(err, ErrExchange) {
+		t.Errorf("Exchange(wrong verifier) error = %v, want %v", err, ErrExchange)
+	}
+}
+
+func TestVerifyRejectsInvalidTokens(t *testing.T) {
+	m := newMockProvider(t)
+	provider := m.discover(t)
+
+	tests := []struct {
+		name   string
+		modify func(map[string]any)
+	}{
+		{"wrong issuer", func(c map[string]any) { c["iss"] = "https://evil.example.com" }},
+		{"wrong audience", func(c map[string]any) { c["aud"] = "someone-else" }},
+		{"foreign azp", func(c map[string]any) { c["aud"] = []string{testClientID, "other"}; c["azp"] = "other" }},
+		{"expired", func(c map[string]any) { c["exp"] = time.Now().Add(-time.Hour).Unix() }},
+		{"issued in the future", func(c map[string]any) { c["iat"] = time.Now().Add(time.Hour).Unix() }},
+		{"wrong nonce", func(c map[string]any) { c["nonce"] = "replayed" }},
+		{"no subject", func(c map[string]any) { delete(c, "sub") }},
+	}
+
+	for _, test := range tests {
+		claims := m.validClaims("nonce")
+		test.modify(claims)
+		if _, err := provider.Verify(context.Background(), m.sign(claims), "nonce"); !errors.Is(err, ErrInvalidToken) {
+			t.Errorf("%v: Verify error = %v, want %v", test.name, err, ErrInvalidToken)
+		}
+	}
+
+	token := m.sign(m.validClaims("nonce"))
+	if _, err := provider.Verify(context.Background(), token, "nonce"); err != nil {
+		t.Fatalf("Verify(valid token): %v", err)
+	}
+
+	parts := strings.Split(token, ".")
+	tampered, _ := json.Marshal(m.validClaims("nonce"))
+	tampered = []byte(strings.Replace(string(tampered), "user-1", "user-2", 1))
+	forged := parts[0] + "." + base64.RawURLEncoding.EncodeToString(tampered) + "." + parts[2]
+	if _, err := provider.Verify(context.Background(), forged, "nonce"); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Verify(tampered payload) error = %v, want %v", err, ErrInvalidToken)
+	}
+
+	none := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none"}`)) + "." + parts[1] + "."
+	if _, err := provider.Verify(context.Background(), none, "nonce"); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Verify(alg none) error = %v, want %v", err, ErrInvalidToken)
+	}
+}
+
+func TestVerifyPicksUpRotatedKeys(t *testing.T) {
+	defer func(interval time.Duration) { keyRefreshInterval = interval }(keyRefreshInterval)
+	keyRefreshInterval = 0
+
+	m := newMockProvider(t)
+	provider := m.discover(t)
+
+	if _, err := provider.Verify(context.Background(), m.sign(m.validClaims("nonce")), "nonce"); err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	m.key, m.kid = key, "key-2"
+
+	if _, err := provider.Verify(context.Background(), m.sign(m.validClaims("nonce")), "nonce"); err != nil {
+		t.Errorf("Verify(rotated key): %v", err)
+	}
+}
+
+func TestDiscoverRejectsIssuerMismatch(t *testing.T) {
+	m := newMockProvider(t)
+	m.issuer = "https://other-tenant.example.com"
+
+	_, err := Discover(context.Background(), Config{
+		IssuerURL: m.server.URL,
+		ClientID:  testClientID,
+	})
+	if err == nil {
+		t.Errorf("Discover succeeded for a mismatched issuer")
+	}
+}
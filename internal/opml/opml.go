@@ -0,0 +1,176 @@
+package opml
+
+import (
+	"encoding/xml"
+	"errors"
+	"io"
+	"strings"
+	"time"
+)
+
+var ErrInvalidDocument = errors.New("Invalid OPML document")
+
+type OPML struct {
+	XMLName xml.Name `xml:"opml"`
+	Version string   `xml:"version,attr"`
+	Head    Head     `xml:"head"`
+	Body    Body     `xml:"body"`
+}
+
+type Head struct {
+	Title       string `xml:"title,omitempty"`
+	DateCreated string `xml:"dateCreated,omitempty"`
+}
+
+type Body struct {
+	Outlines []Outline `xml:"outline"`
+}
+
+type Outline struct {
+	Text     string    `xml:"text,attr"`
+	Title    string    `xml:"title,attr,omitempty"`
+	Type     string    `xml:"type,attr,omitempty"`
+	XMLURL   string    `xml:"xmlUrl,attr,omitempty"`
+	HTMLURL  string    `xml:"htmlUrl,attr,omitempty"`
+	Outlines []Outline `xml:"outline"`
+}
+
+// Entry is a single subscription found in an OPML document.
+type Entry struct {
+	Title   string
+	XMLURL  string
+	HTMLURL string
+	// Folder is the title of the enclosing outlines joined with " / ", or
+	// empty for top level subscriptions.
+	Folder string
+}
+
+// UnmarshalXML reads outline attributes case-insensitively since OPML 1.0
+// exporters disagree on the spelling of xmlUrl and htmlUrl.
+func (o *Outline) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	for _, attr := range start.Attr {
+		switch strings.ToLower(attr.Name.Local) {
+		case "text":
+			o.Text = attr.Value
+		case "title":
+			o.Title = attr.Value
+		case "type":
+			o.Type = attr.Value
+		case "xmlurl":
+			o.XMLURL = attr.Value
+		case "htmlurl":
+			o.HTMLURL = attr.Value
+		}
+	}
+
+	children := struct {
+		Outlines []Outline `xml:"outline"`
+	}{}
+	if err := d.DecodeElement(&children, &start); err != nil {
+		return err
+	}
+	o.Outlines = children.Outlines
+
+	return nil
+}
+
+// New returns an empty OPML 2.0 document.
+func New(title string, created time.Time) *OPML {
+	return &OPML{
+		Version: "2.0",
+		Head: Head{
+			Title:       title,
+			DateCreated: created.UTC().Format(time.RFC1123Z),
+		},
+	}
+}
+
+// AddEntry appends a subscription to the document, nesting it under folder
+// outlines built from entry.Folder, the reverse of what Entries does.
+func (o *OPML) AddEntry(entry Entry) {
+	outlines := &o.Body.Outlines
+	if entry.Folder != "" {
+		for _, name := range strings.Split(entry.Folder, " / ") {
+			outlines = folderOutlines(outlines, name)
+		}
+	}
+
+	*outlines = append(*outlines, Outline{
+		Text:    entry.Title,
+		Title:   entry.Title,
+		Type:    "rss",
+		XMLURL:  entry.XMLURL,
+		HTMLURL: entry.HTMLURL,
+	})
+}
+
+func folderOutlines(outlines *[]Outline, name string) *[]Outline {
+	for i := range *outlines {
+		v := &(*outlines)[i]
+		if v.XMLURL == "" && v.Text == name {
+			return &v.Outlines
+		}
+	}
+
+	*outlines = append(*outlines, Outline{
+		Text:  name,
+		Title: name,
+	})
+	return &(*outlines)[len(*outlines)-1].Outlines
+}
+
+// Write encodes the document, XML declaration included.
+func (o *OPML) Write(w io.Writer) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(o); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+// Parse decodes an OPML 1.0 or 2.0 document.
+func Parse(r io.Reader) (*OPML, error) {
+	doc := new(OPML)
+	if err := xml.NewDecoder(r).Decode(doc); err != nil {
+		return nil, ErrInvalidDocument
+	}
+	return doc, nil
+}
+
+// Entries flattens the outline tree into the list of subscriptions it
+// contains. Outlines without an xmlUrl are treated as folders.
+func (o *OPML) Entries() []Entry {
+	var entries []Entry
+	collectEntries(o.Body.Outlines, nil, &entries)
+	return entries
+}
+
+func collectEntries(outlines []Outline, folders []string, entries *[]Entry) {
+	for _, v := range outlines {
+		title := strings.TrimSpace(v.Title)
+		if title == "" {
+			title = strings.TrimSpace(v.Text)
+		}
+
+		if xmlURL := strings.TrimSpace(v.XMLURL); xmlURL != "" {
+			*entries = append(*entries, Entry{
+				Title:   title,
+				XMLURL:  xmlURL,
+				HTMLURL: strings.TrimSpace(v.HTMLURL),
+				Folder:  strings.Join(folders, " / "),
+			})
+			continue
+		}
+
+		nested := folders
+		if title != "" {
+			nested = append(folders[:len(folders):len(folders)], title)
+		}
+		collectEntries(v.Outlines, nested, entries)
+	}
+}
@@ -0,0 +1,90 @@
+package opml
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+const sampleDocument = `<?xml version="1.0" encoding="UTF-8"?>
+<opml version="1.0">
+  <head><title>Subscriptions</title></head>
+  <body>
+    <outline text="Top Level" type="rss" xmlUrl="https://example.com/top.xml" htmlUrl="https://example.com"/>
+    <outline text="Engineering">
+      <outline text="Go Blog" title="The Go Blog" type="rss" xmlUrl="https://go.dev/blog/feed.atom"/>
+      <outline text="Databases">
+        <outline text="Postgres" type="rss" xmlurl="https://postgresql.org/news.rss" HTMLURL="https://postgresql.org"/>
+      </outline>
+    </outline>
+    <outline text="Empty Folder"/>
+  </body>
+</opml>`
+
+func TestParseEntries(t *testing.T) {
+	doc, err := Parse(strings.NewReader(sampleDocument))
+	if err != nil {
+		t.Fatalf("Failed to parse document: %v", err)
+	}
+
+	expected := []Entry{
+		{Title: "Top Level", XMLURL: "https://example.com/top.xml", HTMLURL: "https://example.com"},
+		{Title: "The Go Blog", XMLURL: "https://go.dev/blog/feed.atom", Folder: "Engineering"},
+		{Title: "Postgres", XMLURL: "https://postgresql.org/news.rss", HTMLURL: "https://postgresql.org", Folder: "Engineering / Databases"},
+	}
+
+	actual := doc.Entries()
+	if len(actual) != len(expected) {
+		t.Fatalf("Expected %v entries, got %v: %v", len(expected), len(actual), actual)
+	}
+	for i := range expected {
+		if actual[i] != expected[i] {
+			t.Errorf("Expected entry %v, got %v", expected[i], actual[i])
+		}
+	}
+}
+
+func TestParseInvalidDocument(t *testing.T) {
+	if _, err := Parse(strings.NewReader("<opml><body>")); !errors.Is(err, ErrInvalidDocument) {
+		t.Errorf("Expected ErrInvalidDocument, got %v", err)
+	}
+}
+
+func TestWriteRoundTrip(t *testing.T) {
+	expected := []Entry{
+		{Title: "Go Blog", XMLURL: "https://go.dev/blog/feed.atom", HTMLURL: "https://go.dev/blog", Folder: "Engineering"},
+		{Title: "Postgres", XMLURL: "https://postgresql.org/news.rss", Folder: "Engineering / Databases"},
+		{Title: "Kernel", XMLURL: "https://kernel.org/feeds/kdist.xml", Folder: "Engineering"},
+		{Title: "Top Level", XMLURL: "https://example.com/top.xml"},
+	}
+
+	doc := New("Subscriptions", time.Now())
+	for _, v := range expected {
+		doc.AddEntry(v)
+	}
+
+	buf := new(bytes.Buffer)
+	if err := doc.Write(buf); err != nil {
+		t.Fatalf("Failed to write document: %v", err)
+	}
+
+	parsed, err := Parse(buf)
+	if err != nil {
+		t.Fatalf("Failed to parse written document: %v", err)
+	}
+	if parsed.Version != "2.0" {
+		t.Errorf("Expected version 2.0, got %v", parsed.Version)
+	}
+
+	actual := parsed.Entries()
+	if len(actual) != len(expected) {
+		t.Fatalf("Expected %v entries, got %v: %v", len(expected), len(actual), actual)
+	}
+	for i := range expected {
+		if actual[i] != expected[i] {
+			t.Errorf("Expected entry %v, got %v", expected[i], actual[i])
+		}
+	}
+}
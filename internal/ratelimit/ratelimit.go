@@ -0,0 +1,68 @@
+// Package ratelimit limits how often a client may do something by counting
+// its attempts in fixed time windows.
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// Limiter allows each key at most limit attempts per window.
+type Limiter struct {
+	limit  int
+	window time.Duration
+	now    func() time.Time
+
+	mu        sync.Mutex
+	counts    map[string]*count
+	lastSweep time.Time
+}
+
+type count struct {
+	start time.Time
+	n     int
+}
+
+func New(limit int, window time.Duration) *Limiter {
+	return &Limiter{
+		limit:  limit,
+		window: window,
+		now:    time.Now,
+		counts: make(map[string]*count),
+	}
+}
+
+// Allow records an attempt by key. It reports whether the attempt is within
+// the limit and, if not, how long until the key may try again.
+func (l *Limiter) Allow(key string) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	c, ok := l.counts[key]
+	if !ok || now.Sub(c.start) >= l.window {
+		c = &count{start: now}
+		l.counts[key] = c
+	}
+	if c.n >= l.limit {
+		return false, c.start.Add(l.window).Sub(now)
+	}
+	c.n++
+	return true, 0
+}
+
+// sweep forgets keys whose window has ended, at most once per window, so
+// the map doesn't grow with every client ever seen.
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < l.window {
+		return
+	}
+	for key, c := range l.counts {
+		if now.Sub(c.start) >= l.window {
+			delete(l.counts, key)
+		}
+	}
+	l.lastSweep = now
+}
@@ -0,0 +1,49 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAllow(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	l := New(2, time.Minute)
+	l.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		if ok, _ := l.Allow("a"); !ok {
+			t.Fatalf("attempt %v of a was refused", i+1)
+		}
+	}
+	ok, retryAfter := l.Allow("a")
+	if ok {
+		t.Fatalf("third attempt of a was allowed")
+	}
+	if retryAfter != time.Minute {
+		t.Errorf("retryAfter = %v, want %v", retryAfter, time.Minute)
+	}
+
+	if ok, _ := l.Allow("b"); !ok {
+		t.Errorf("b was refused because of a")
+	}
+
+	now = now.Add(time.Minute)
+	if ok, _ := l.Allow("a"); !ok {
+		t.Errorf("a was refused after its window ended")
+	}
+}
+
+func TestSweepForgetsExpiredKeys(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	l := New(1, time.Minute)
+	l.now = func() time.Time { return now }
+
+	l.Allow("a")
+	l.Allow("b")
+	now = now.Add(2 * time.Minute)
+	l.Allow("c")
+
+	if len(l.counts) != 1 {
+		t.Errorf("len(counts) = %v, want 1", len(l.counts))
+	}
+}
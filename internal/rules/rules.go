@@ -0,0 +1,200 @@
+// Package rules evaluates user-defined filter rules against posts.
+package rules
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+type Field string
+
+const (
+	FieldTitle       Field = "title"
+	FieldDescription Field = "description"
+	FieldContent     Field = "content"
+	FieldAuthor      Field = "author"
+	FieldCategory    Field = "category"
+	FieldFeed        Field = "feed"
+	FieldURL         Field = "url"
+)
+
+type Operator string
+
+const (
+	// OperatorContains matches a case-insensitive substring.
+	OperatorContains Operator = "contains"
+	// OperatorWord matches a case-insensitive whole word or phrase.
+	OperatorWord Operator = "word"
+	// OperatorRegex matches a regular expression in RE2 syntax.
+	OperatorRegex Operator = "regex"
+)
+
+type Action string
+
+const (
+	ActionHide   Action = "hide"
+	ActionRead   Action = "read"
+	ActionStar   Action = "star"
+	ActionTag    Action = "tag"
+	ActionNotify Action = "notify"
+)
+
+var (
+	ErrNoConditions = errors.New("Rule needs at least one condition")
+	ErrNoActions    = errors.New("Rule needs at least one action")
+	ErrMissingTag   = errors.New("Tag action needs a tag")
+)
+
+type Condition struct {
+	Field    Field    `json:"field"`
+	Operator Operator `json:"operator"`
+	Value    string   `json:"value"`
+}
+
+type Rule struct {
+	Conditions []Condition `json:"conditions"`
+	// MatchAll requires every condition to match instead of any of them.
+	MatchAll bool     `json:"match_all"`
+	Actions  []Action `json:"actions"`
+	Tag      string   `json:"tag,omitempty"`
+}
+
+// Post holds the parts of a post that conditions can look at. Feed
+// conditions match either the feed name or its ID.
+type Post struct {
+	Title       string
+	Description string
+	Content     string
+	Author      string
+	Categories  []string
+	FeedID      string
+	FeedName    string
+	URL         string
+}
+
+// Matcher is a compiled rule.
+type Matcher struct {
+	matchAll   bool
+	conditions []compiledCondition
+}
+
+type compiledCondition struct {
+	field Field
+	match func(string) bool
+}
+
+// Validate checks the whole rule, actions included.
+func (r Rule) Validate() error {
+	if len(r.Actions) == 0 {
+		return ErrNoActions
+	}
+	for _, action := range r.Actions {
+		switch action {
+		case ActionHide, ActionRead, ActionStar, ActionNotify:
+		case ActionTag:
+			if strings.TrimSpace(r.Tag) == "" {
+				return ErrMissingTag
+			}
+		default:
+			return fmt.Errorf("Unknown action %q", action)
+		}
+	}
+	_, err := Compile(r)
+	return err
+}
+
+// Compile prepares the rule's conditions for matching. Actions are not
+// looked at, so a rule can be tried out before it is complete.
+func Compile(rule Rule) (*Matcher, error) {
+	if len(rule.Conditions) == 0 {
+		return nil, ErrNoConditions
+	}
+
+	m := &Matcher{matchAll: rule.MatchAll}
+	for _, condition := range rule.Conditions {
+		c, err := compileCondition(condition)
+		if err != nil {
+			return nil, err
+		}
+		m.conditions = append(m.conditions, c)
+	}
+	return m, nil
+}
+
+func compileCondition(condition Condition) (compiledCondition, error) {
+	switch condition.Field {
+	case FieldTitle, FieldDescription, FieldContent, FieldAuthor, FieldCategory, FieldFeed, FieldURL:
+	default:
+		return compiledCondition{}, fmt.Errorf("Unknown field %q", condition.Field)
+	}
+	if condition.Value == "" {
+		return compiledCondition{}, fmt.Errorf("Condition on %v needs a value", condition.Field)
+	}
+
+	c := compiledCondition{field: condition.Field}
+	switch condition.Operator {
+	case OperatorContains:
+		value := strings.ToLower(condition.Value)
+		c.match = func(s string) bool {
+			return strings.Contains(strings.ToLower(s), value)
+		}
+	case OperatorWord:
+		re, err := regexp.Compile(`(?i)(^|\W)` + regexp.QuoteMeta(condition.Value) + `($|\W)`)
+		if err != nil {
+			return compiledCondition{}, err
+		}
+		c.match = re.MatchString
+	case OperatorRegex:
+		re, err := regexp.Compile(condition.Value)
+		if err != nil {
+			return compiledCondition{}, fmt.Errorf("Invalid regex %q: %v", condition.Value, err)
+		}
+		c.match = re.MatchString
+	default:
+		return compiledCondition{}, fmt.Errorf("Unknown operator %q", condition.Operator)
+	}
+	return c, nil
+}
+
+// Match reports whether the post satisfies the rule's conditions.
+func (m *Matcher) Match(post Post) bool {
+	for _, c := range m.conditions {
+		matched := c.matches(post)
+		if matched && !m.matchAll {
+			return true
+		}
+		if !matched && m.matchAll {
+			return false
+		}
+	}
+	return m.matchAll
+}
+
+func (c compiledCondition) matches(post Post) bool {
+	var values []string
+	switch c.field {
+	case FieldTitle:
+		values = []string{post.Title}
+	case FieldDescription:
+		values = []string{post.Description}
+	case FieldContent:
+		values = []string{post.Content}
+	case FieldAuthor:
+		values = []string{post.Author}
+	case FieldCategory:
+		values = post.Categories
+	case FieldFeed:
+		values = []string{post.FeedName, post.FeedID}
+	case FieldURL:
+		values = []string{post.URL}
+	}
+
+	for _, v := range values {
+		if v != "" && c.match(v) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,70 @@
+package rules
+
+import "testing"
+
+func TestValidateErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		rule Rule
+	}{
+		{"no conditions", Rule{Actions: []Action{ActionRead}}},
+		{"no actions", Rule{Conditions: []Condition{{FieldTitle, OperatorContains, "go"}}}},
+		{"unknown action", Rule{Conditions: []Condition{{FieldTitle, OperatorContains, "go"}}, Actions: []Action{"delete"}}},
+		{"tag without tag", Rule{Conditions: []Condition{{FieldTitle, OperatorContains, "go"}}, Actions: []Action{ActionTag}}},
+		{"unknown field", Rule{Conditions: []Condition{{"body", OperatorContains, "go"}}, Actions: []Action{ActionRead}}},
+		{"unknown operator", Rule{Conditions: []Condition{{FieldTitle, "like", "go"}}, Actions: []Action{ActionRead}}},
+		{"empty value", Rule{Conditions: []Condition{{FieldTitle, OperatorContains, ""}}, Actions: []Action{ActionRead}}},
+		{"bad regex", Rule{Conditions: []Condition{{FieldTitle, OperatorRegex, "("}}, Actions: []Action{ActionRead}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.rule.Validate(); err == nil {
+				t.Errorf("Validate(%+v) succeeded, want error", tt.rule)
+			}
+		})
+	}
+}
+
+func TestMatch(t *testing.T) {
+	post := Post{
+		Title:      "Generics in Go 1.22",
+		URL:        "https://example.com/posts/generics",
+		Author:     "Jane Doe",
+		Categories: []string{"golang", "programming"},
+		FeedID:     "0b8b1b54-8c1c-4b2e-a0c4-5d6b5fd1e4a7",
+		FeedName:   "Example Blog",
+	}
+
+	tests := []struct {
+		name       string
+		conditions []Condition
+		matchAll   bool
+		want       bool
+	}{
+		{"contains ignores case", []Condition{{FieldTitle, OperatorContains, "GENERICS"}}, true, true},
+		{"contains misses", []Condition{{FieldTitle, OperatorContains, "rust"}}, true, false},
+		{"word match", []Condition{{FieldTitle, OperatorWord, "go"}}, true, true},
+		{"word inside another word", []Condition{{FieldTitle, OperatorWord, "gen"}}, true, false},
+		{"regex", []Condition{{FieldURL, OperatorRegex, `/posts/\w+$`}}, true, true},
+		{"category", []Condition{{FieldCategory, OperatorWord, "golang"}}, true, true},
+		{"feed by name", []Condition{{FieldFeed, OperatorContains, "example"}}, true, true},
+		{"feed by id", []Condition{{FieldFeed, OperatorContains, "0b8b1b54-8c1c-4b2e-a0c4-5d6b5fd1e4a7"}}, true, true},
+		{"empty field never matches", []Condition{{FieldContent, OperatorRegex, ".*"}}, true, false},
+		{"all requires every condition", []Condition{{FieldTitle, OperatorContains, "go"}, {FieldAuthor, OperatorContains, "smith"}}, true, false},
+		{"any needs one condition", []Condition{{FieldTitle, OperatorContains, "go"}, {FieldAuthor, OperatorContains, "smith"}}, false, true},
+		{"any with no match", []Condition{{FieldTitle, OperatorContains, "rust"}, {FieldAuthor, OperatorContains, "smith"}}, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := Compile(Rule{Conditions: tt.conditions, MatchAll: tt.matchAll})
+			if err != nil {
+				t.Fatalf("Compile: %v", err)
+			}
+			if got := m.Match(post); got != tt.want {
+				t.Errorf("Match() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
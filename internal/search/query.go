@@ -0,0 +1,126 @@
+package search
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+)
+
+var ErrEmptyQuery = errors.New("Empty search query")
+
+// ToTSQuery converts a user search string into the to_tsquery syntax.
+//
+// Terms are ANDed together. "quoted phrases" match words next to each other,
+// a trailing * turns a term into a prefix match, a leading - excludes it and
+// OR between two terms matches either of them. Everything else that carries
+// meaning in to_tsquery is stripped so user input can't produce a syntax
+// error.
+func ToTSQuery(input string) (string, error) {
+	var groups [][]string
+	pendingOr := false
+
+	for _, token := range tokenize(input) {
+		if !token.quoted {
+			switch token.text {
+			case "OR":
+				pendingOr = len(groups) > 0
+				continue
+			case "AND":
+				pendingOr = false
+				continue
+			}
+		}
+
+		term := token.term()
+		if term == "" {
+			continue
+		}
+
+		if pendingOr {
+			groups[len(groups)-1] = append(groups[len(groups)-1], term)
+		} else {
+			groups = append(groups, []string{term})
+		}
+		pendingOr = false
+	}
+
+	if len(groups) == 0 {
+		return "", ErrEmptyQuery
+	}
+
+	parts := make([]string, len(groups))
+	for i, group := range groups {
+		if len(group) == 1 || len(groups) == 1 {
+			parts[i] = strings.Join(group, " | ")
+		} else {
+			parts[i] = "(" + strings.Join(group, " | ") + ")"
+		}
+	}
+	return strings.Join(parts, " & "), nil
+}
+
+type token struct {
+	text    string
+	quoted  bool
+	negated bool
+}
+
+func tokenize(input string) []token {
+	var tokens []token
+	runes := []rune(input)
+
+	for i := 0; i < len(runes); {
+		if unicode.IsSpace(runes[i]) {
+			i++
+			continue
+		}
+
+		negated := false
+		if runes[i] == '-' {
+			negated = true
+			i++
+		}
+
+		if i < len(runes) && runes[i] == '"' {
+			end := i + 1
+			for end < len(runes) && runes[end] != '"' {
+				end++
+			}
+			tokens = append(tokens, token{text: string(runes[i+1 : end]), quoted: true, negated: negated})
+			i = end + 1
+			continue
+		}
+
+		end := i
+		for end < len(runes) && !unicode.IsSpace(runes[end]) {
+			end++
+		}
+		tokens = append(tokens, token{text: string(runes[i:end]), negated: negated})
+		i = end
+	}
+
+	return tokens
+}
+
+func (t token) term() string {
+	prefix := !t.quoted && strings.HasSuffix(t.text, "*")
+
+	words := strings.FieldsFunc(strings.ToLower(t.text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(words) == 0 {
+		return ""
+	}
+
+	term := strings.Join(words, " <-> ")
+	if prefix {
+		term += ":*"
+	}
+	if len(words) > 1 {
+		term = "(" + term + ")"
+	}
+	if t.negated {
+		term = "!" + term
+	}
+	return term
+}
@@ -0,0 +1,44 @@
+package search
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestToTSQuery(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"golang", "golang"},
+		{"golang generics", "golang & generics"},
+		{"golang AND generics", "golang & generics"},
+		{`"machine learning" OR ai`, "(machine <-> learning) | ai"},
+		{"rust OR go OR zig tooling", "(rust | go | zig) & tooling"},
+		{"postgr*", "postgr:*"},
+		{"kubernetes -helm", "kubernetes & !helm"},
+		{`-"breaking change"`, "!(breaking <-> change)"},
+		{"c++ & (x | y):*", "c & x & y:*"},
+		{"OR golang", "golang"},
+		{"Ünïcode WÖRDS", "ünïcode & wörds"},
+	}
+
+	for _, test := range tests {
+		actual, err := ToTSQuery(test.input)
+		if err != nil {
+			t.Errorf("ToTSQuery(%q) returned error: %v", test.input, err)
+			continue
+		}
+		if actual != test.expected {
+			t.Errorf("ToTSQuery(%q): expected %v, got %v", test.input, test.expected, actual)
+		}
+	}
+}
+
+func TestToTSQueryEmpty(t *testing.T) {
+	for _, input := range []string{"", "   ", "&|!", `""`, "OR AND"} {
+		if _, err := ToTSQuery(input); !errors.Is(err, ErrEmptyQuery) {
+			t.Errorf("ToTSQuery(%q): expected ErrEmptyQuery, got %v", input, err)
+		}
+	}
+}
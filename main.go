@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"flag"
 	"fmt"
@@ -10,14 +11,37 @@ import (
 	"time"
 
 	"github.com/JustinLi007/rss-aggregator/internal/database"
+	"github.com/JustinLi007/rss-aggregator/internal/ratelimit"
 	"github.com/joho/godotenv"
 	_ "github.com/lib/pq"
 )
 
 type apiConfig struct {
-	DB        *database.Queries
+	DB *database.Queries
+	// conn is the connection pool behind DB, used to start transactions.
+	conn      *sql.DB
 	nextFeeds []Feed
 	limit     int32
+	// SSO is nil unless an OpenID Connect provider is configured.
+	SSO *ssoConfig
+	// RegistrationMode is one of registrationOpen, registrationInvite and
+	// registrationClosed.
+	RegistrationMode string
+}
+
+// withTx runs fn with queries bound to a transaction, committing it when fn
+// returns nil and rolling it back otherwise.
+func (cfg *apiConfig) withTx(ctx context.Context, fn func(db *database.Queries) error) error {
+	tx, err := cfg.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(cfg.DB.WithTx(tx)); err != nil {
+		return err
+	}
+	return tx.Commit()
 }
 
 func main() {
@@ -47,8 +71,34 @@ func main() {
 		log.Fatal(err)
 	}
 
+	sso, err := loadSSOConfig(context.Background())
+	if err != nil {
+		log.Fatalf("Failed to set up single sign-on: %v", err)
+	}
+
+	registrationMode, err := loadRegistrationMode()
+	if err != nil {
+		log.Fatal(err)
+	}
+	registrationRateLimit, err := loadRegistrationRateLimit()
+	if err != nil {
+		log.Fatal(err)
+	}
+	var registrationLimiter *ratelimit.Limiter
+	if registrationRateLimit > 0 {
+		registrationLimiter = ratelimit.New(registrationRateLimit, registrationRateWindow)
+	}
+	loginLimiter := ratelimit.New(loginRateLimit, loginRateWindow)
+
 	apiCfg := apiConfig{
-		DB: database.New(db),
+		DB:               database.New(db),
+		conn:             db,
+		SSO:              sso,
+		RegistrationMode: registrationMode,
+	}
+
+	if err := bootstrapAdmin(context.Background(), apiCfg.DB, os.Getenv("ADMIN_BOOTSTRAP_NAME"), os.Getenv("ADMIN_BOOTSTRAP_PASSWORD")); err != nil {
+		log.Fatalf("Failed to bootstrap admin: %v", err)
 	}
 
 	serveMux := http.NewServeMux()
@@ -56,18 +106,101 @@ func main() {
 
 	serveMux.HandleFunc("GET /v1/healthz", handlerHealthz)
 	serveMux.HandleFunc("GET /v1/err", handlerError)
-
-	serveMux.HandleFunc("POST /v1/users", apiCfg.handlerCreateUsers)
-	serveMux.HandleFunc("GET /v1/users", apiCfg.middlewareAuth(apiCfg.handlerGetUserAuthed))
-
-	serveMux.HandleFunc("POST /v1/feeds", apiCfg.middlewareAuth(apiCfg.handlerCreateFeedsAuthed))
+	serveMux.HandleFunc("GET /debug/vars", apiCfg.middlewareAuth(scopeAdmin, handlerDebugVarsAuthed))
+
+	serveMux.HandleFunc("POST /v1/users", middlewareRateLimit(registrationLimiter, apiCfg.handlerCreateUsers))
+	serveMux.HandleFunc("GET /v1/users", apiCfg.middlewareAuth(scopeNone, apiCfg.handlerGetUserAuthed))
+	serveMux.HandleFunc("DELETE /v1/users/me", apiCfg.middlewareAuth(scopeAccountWrite, apiCfg.handlerDeleteAccountAuthed))
+	serveMux.HandleFunc("GET /v1/users/me/export", apiCfg.middlewareAuth(scopeAccountWrite, apiCfg.handlerExportAccountAuthed))
+	serveMux.HandleFunc("PUT /v1/users/password", apiCfg.middlewareAuth(scopeAccountWrite, apiCfg.handlerSetPasswordAuthed))
+
+	serveMux.HandleFunc("POST /v1/login", middlewareRateLimit(loginLimiter, apiCfg.handlerLogin))
+	serveMux.HandleFunc("GET /v1/oidc/login", apiCfg.handlerSSOLogin)
+	serveMux.HandleFunc("GET /v1/oidc/callback", apiCfg.handlerSSOCallback)
+	serveMux.HandleFunc("POST /v1/logout", apiCfg.middlewareAuth(scopeNone, apiCfg.handlerLogoutAuthed))
+	serveMux.HandleFunc("GET /v1/sessions", apiCfg.middlewareAuth(scopeAccountWrite, apiCfg.handlerGetSessionsAuthed))
+	serveMux.HandleFunc("DELETE /v1/sessions/{sessionID}", apiCfg.middlewareAuth(scopeAccountWrite, apiCfg.handlerRevokeSessionAuthed))
+
+	serveMux.HandleFunc("GET /v1/admin/users", apiCfg.middlewareAuth(scopeAdmin, apiCfg.handlerListUsersAuthed))
+	serveMux.HandleFunc("GET /v1/admin/users/{userID}", apiCfg.middlewareAuth(scopeAdmin, apiCfg.handlerGetUserByIDAuthed))
+	serveMux.HandleFunc("DELETE /v1/admin/users/{userID}", apiCfg.middlewareAuth(scopeAdmin, apiCfg.handlerDeleteUserAuthed))
+	serveMux.HandleFunc("GET /v1/admin/users/{userID}/feed_follows", apiCfg.middlewareAuth(scopeAdmin, apiCfg.handlerGetUserFeedFollowsAuthed))
+	serveMux.HandleFunc("POST /v1/admin/users/{userID}/disable", apiCfg.middlewareAuth(scopeAdmin, apiCfg.handlerDisableUserAuthed))
+	serveMux.HandleFunc("POST /v1/admin/users/{userID}/enable", apiCfg.middlewareAuth(scopeAdmin, apiCfg.handlerEnableUserAuthed))
+	serveMux.HandleFunc("PUT /v1/admin/users/{userID}/role", apiCfg.middlewareAuth(scopeAdmin, apiCfg.handlerSetUserRoleAuthed))
+	serveMux.HandleFunc("POST /v1/admin/users/{userID}/reset_api_keys", apiCfg.middlewareAuth(scopeAdmin, apiCfg.handlerResetUserAPIKeysAuthed))
+
+	serveMux.HandleFunc("POST /v1/admin/invites", apiCfg.middlewareAuth(scopeAdmin, apiCfg.handlerCreateInviteAuthed))
+	serveMux.HandleFunc("GET /v1/admin/invites", apiCfg.middlewareAuth(scopeAdmin, apiCfg.handlerGetInvitesAuthed))
+	serveMux.HandleFunc("DELETE /v1/admin/invites/{inviteID}", apiCfg.middlewareAuth(scopeAdmin, apiCfg.handlerRevokeInviteAuthed))
+
+	serveMux.HandleFunc("POST /v1/api_keys", apiCfg.middlewareAuth(scopeAccountWrite, apiCfg.handlerCreateAPIKeyAuthed))
+	serveMux.HandleFunc("GET /v1/api_keys", apiCfg.middlewareAuth(scopeAccountWrite, apiCfg.handlerGetAPIKeysAuthed))
+	serveMux.HandleFunc("DELETE /v1/api_keys/{apiKeyID}", apiCfg.middlewareAuth(scopeAccountWrite, apiCfg.handlerRevokeAPIKeyAuthed))
+	serveMux.HandleFunc("POST /v1/api_keys/{apiKeyID}/rotate", apiCfg.middlewareAuth(scopeAccountWrite, apiCfg.handlerRotateAPIKeyAuthed))
+
+	serveMux.HandleFunc("POST /v1/feeds", apiCfg.middlewareAuth(scopeFeedsWrite, apiCfg.handlerCreateFeedsAuthed))
 	serveMux.HandleFunc("GET /v1/feeds", apiCfg.handlerGetFeeds)
-
-	serveMux.HandleFunc("POST /v1/feed_follows", apiCfg.middlewareAuth(apiCfg.handlerFollowFeedAuthed))
-	serveMux.HandleFunc("GET /v1/feed_follows", apiCfg.middlewareAuth(apiCfg.handlerGetFeedFollowsAuthed))
-	serveMux.HandleFunc("DELETE /v1/feed_follows/{feedFollowID}", apiCfg.middlewareAuth(apiCfg.handlerUnfollowFeedAuthed))
-
-	serveMux.HandleFunc("GET /v1/posts", apiCfg.middlewareAuth(apiCfg.handlerGetPostsByUser))
+	serveMux.HandleFunc("PATCH /v1/feeds/{feedID}", apiCfg.middlewareAuth(scopeFeedsWrite, apiCfg.handlerUpdateFeedAuthed))
+	serveMux.HandleFunc("DELETE /v1/feeds/{feedID}", apiCfg.middlewareAuth(scopeFeedsWrite, apiCfg.handlerDeleteFeedAuthed))
+	serveMux.HandleFunc("PUT /v1/feeds/{feedID}/retention", apiCfg.middlewareAuth(scopeFeedsWrite, apiCfg.handlerSetFeedRetentionAuthed))
+
+	serveMux.HandleFunc("POST /v1/feed_follows", apiCfg.middlewareAuth(scopeFollowsWrite, apiCfg.handlerFollowFeedAuthed))
+	serveMux.HandleFunc("GET /v1/feed_follows", apiCfg.middlewareAuth(scopeFollowsRead, apiCfg.handlerGetFeedFollowsAuthed))
+	serveMux.HandleFunc("GET /v1/follow_list", apiCfg.middlewareAuth(scopeFollowsRead, apiCfg.handlerGetFollowListAuthed))
+	serveMux.HandleFunc("PATCH /v1/feed_follows/{feedFollowID}", apiCfg.middlewareAuth(scopeFollowsWrite, apiCfg.handlerUpdateFeedFollowAuthed))
+	serveMux.HandleFunc("DELETE /v1/feed_follows/{feedFollowID}", apiCfg.middlewareAuth(scopeFollowsWrite, apiCfg.handlerUnfollowFeedAuthed))
+	serveMux.HandleFunc("PUT /v1/feed_follows/{feedFollowID}/folder", apiCfg.middlewareAuth(scopeFollowsWrite, apiCfg.handlerSetFeedFollowFolderAuthed))
+
+	serveMux.HandleFunc("POST /v1/opml/import", apiCfg.middlewareAuth(scopeFollowsWrite, apiCfg.handlerImportOPMLAuthed))
+	serveMux.HandleFunc("GET /v1/opml/export", apiCfg.middlewareAuth(scopeFollowsRead, apiCfg.handlerExportOPMLAuthed))
+
+	serveMux.HandleFunc("POST /v1/output_tokens", apiCfg.middlewareAuth(scopeAccountWrite, apiCfg.handlerCreateOutputFeedTokenAuthed))
+	serveMux.HandleFunc("GET /v1/output_tokens", apiCfg.middlewareAuth(scopeAccountWrite, apiCfg.handlerGetOutputFeedTokensAuthed))
+	serveMux.HandleFunc("DELETE /v1/output_tokens/{tokenID}", apiCfg.middlewareAuth(scopeAccountWrite, apiCfg.handlerRevokeOutputFeedTokenAuthed))
+	serveMux.HandleFunc("GET /v1/output/{token}/{format}", apiCfg.handlerGetOutputFeed)
+
+	serveMux.HandleFunc("POST /v1/folders", apiCfg.middlewareAuth(scopeFollowsWrite, apiCfg.handlerCreateFolderAuthed))
+	serveMux.HandleFunc("GET /v1/folders", apiCfg.middlewareAuth(scopeFollowsRead, apiCfg.handlerGetFoldersAuthed))
+	serveMux.HandleFunc("PUT /v1/folders/order", apiCfg.middlewareAuth(scopeFollowsWrite, apiCfg.handlerReorderFoldersAuthed))
+	serveMux.HandleFunc("PATCH /v1/folders/{folderID}", apiCfg.middlewareAuth(scopeFollowsWrite, apiCfg.handlerUpdateFolderAuthed))
+	serveMux.HandleFunc("DELETE /v1/folders/{folderID}", apiCfg.middlewareAuth(scopeFollowsWrite, apiCfg.handlerDeleteFolderAuthed))
+
+	serveMux.HandleFunc("POST /v1/saved_searches", apiCfg.middlewareAuth(scopePostsWrite, apiCfg.handlerCreateSavedSearchAuthed))
+	serveMux.HandleFunc("GET /v1/saved_searches", apiCfg.middlewareAuth(scopePostsRead, apiCfg.handlerGetSavedSearchesAuthed))
+	serveMux.HandleFunc("PATCH /v1/saved_searches/{savedSearchID}", apiCfg.middlewareAuth(scopePostsWrite, apiCfg.handlerUpdateSavedSearchAuthed))
+	serveMux.HandleFunc("DELETE /v1/saved_searches/{savedSearchID}", apiCfg.middlewareAuth(scopePostsWrite, apiCfg.handlerDeleteSavedSearchAuthed))
+	serveMux.HandleFunc("GET /v1/saved_searches/{savedSearchID}/posts", apiCfg.middlewareAuth(scopePostsRead, apiCfg.handlerGetSavedSearchPostsAuthed))
+
+	serveMux.HandleFunc("POST /v1/labels", apiCfg.middlewareAuth(scopePostsWrite, apiCfg.handlerCreateLabelAuthed))
+	serveMux.HandleFunc("GET /v1/labels", apiCfg.middlewareAuth(scopePostsRead, apiCfg.handlerGetLabelsAuthed))
+	serveMux.HandleFunc("PATCH /v1/labels/{labelID}", apiCfg.middlewareAuth(scopePostsWrite, apiCfg.handlerUpdateLabelAuthed))
+	serveMux.HandleFunc("DELETE /v1/labels/{labelID}", apiCfg.middlewareAuth(scopePostsWrite, apiCfg.handlerDeleteLabelAuthed))
+
+	serveMux.HandleFunc("POST /v1/filter_rules", apiCfg.middlewareAuth(scopePostsWrite, apiCfg.handlerCreateFilterRuleAuthed))
+	serveMux.HandleFunc("GET /v1/filter_rules", apiCfg.middlewareAuth(scopePostsRead, apiCfg.handlerGetFilterRulesAuthed))
+	serveMux.HandleFunc("POST /v1/filter_rules/dry_run", apiCfg.middlewareAuth(scopePostsRead, apiCfg.handlerDryRunFilterRuleAuthed))
+	serveMux.HandleFunc("PATCH /v1/filter_rules/{filterRuleID}", apiCfg.middlewareAuth(scopePostsWrite, apiCfg.handlerUpdateFilterRuleAuthed))
+	serveMux.HandleFunc("DELETE /v1/filter_rules/{filterRuleID}", apiCfg.middlewareAuth(scopePostsWrite, apiCfg.handlerDeleteFilterRuleAuthed))
+	serveMux.HandleFunc("POST /v1/filter_rules/{filterRuleID}/apply", apiCfg.middlewareAuth(scopePostsWrite, apiCfg.handlerApplyFilterRuleAuthed))
+
+	serveMux.HandleFunc("GET /v1/notifications", apiCfg.middlewareAuth(scopePostsRead, apiCfg.handlerGetNotificationsAuthed))
+	serveMux.HandleFunc("DELETE /v1/notifications/{notificationID}", apiCfg.middlewareAuth(scopePostsWrite, apiCfg.handlerDeleteNotificationAuthed))
+
+	serveMux.HandleFunc("GET /v1/posts", apiCfg.middlewareAuth(scopePostsRead, apiCfg.handlerGetPostsByUser))
+	serveMux.HandleFunc("GET /v1/posts/search", apiCfg.middlewareAuth(scopePostsRead, apiCfg.handlerSearchPostsAuthed))
+	serveMux.HandleFunc("GET /v1/posts/starred", apiCfg.middlewareAuth(scopePostsRead, apiCfg.handlerGetStarredPostsAuthed))
+	serveMux.HandleFunc("POST /v1/posts/read", apiCfg.middlewareAuth(scopePostsWrite, apiCfg.handlerMarkPostsReadAuthed))
+	serveMux.HandleFunc("POST /v1/posts/unread", apiCfg.middlewareAuth(scopePostsWrite, apiCfg.handlerMarkPostsUnreadAuthed))
+	serveMux.HandleFunc("POST /v1/posts/read_all", apiCfg.middlewareAuth(scopePostsWrite, apiCfg.handlerMarkAllPostsReadAuthed))
+	serveMux.HandleFunc("POST /v1/posts/{postID}/read", apiCfg.middlewareAuth(scopePostsWrite, apiCfg.handlerMarkPostReadAuthed))
+	serveMux.HandleFunc("DELETE /v1/posts/{postID}/read", apiCfg.middlewareAuth(scopePostsWrite, apiCfg.handlerMarkPostUnreadAuthed))
+	serveMux.HandleFunc("POST /v1/posts/{postID}/star", apiCfg.middlewareAuth(scopePostsWrite, apiCfg.handlerStarPostAuthed))
+	serveMux.HandleFunc("DELETE /v1/posts/{postID}/star", apiCfg.middlewareAuth(scopePostsWrite, apiCfg.handlerUnstarPostAuthed))
+	serveMux.HandleFunc("POST /v1/posts/{postID}/labels/{labelID}", apiCfg.middlewareAuth(scopePostsWrite, apiCfg.handlerAddPostLabelAuthed))
+	serveMux.HandleFunc("DELETE /v1/posts/{postID}/labels/{labelID}", apiCfg.middlewareAuth(scopePostsWrite, apiCfg.handlerRemovePostLabelAuthed))
+	serveMux.HandleFunc("POST /v1/posts/{postID}/hide", apiCfg.middlewareAuth(scopePostsWrite, apiCfg.handlerHidePostAuthed))
+	serveMux.HandleFunc("DELETE /v1/posts/{postID}/hide", apiCfg.middlewareAuth(scopePostsWrite, apiCfg.handlerUnhidePostAuthed))
 
 	server := &http.Server{
 		Addr:    ":" + port,
@@ -78,6 +211,23 @@ func main() {
 	const collectionInterval = time.Minute
 	go initScraping(apiCfg.DB, collectionConcurrency, collectionInterval)
 
+	feedGCGracePeriod := defaultFeedGCGracePeriod
+	if value := os.Getenv("FEED_GC_GRACE_PERIOD"); value != "" {
+		feedGCGracePeriod, err = time.ParseDuration(value)
+		if err != nil {
+			log.Fatalf("Invalid FEED_GC_GRACE_PERIOD: %v", err)
+		}
+	}
+	const feedGCInterval = time.Hour
+	go initFeedGC(apiCfg.DB, feedGCInterval, feedGCGracePeriod)
+
+	retention, err := loadRetentionPolicy()
+	if err != nil {
+		log.Fatal(err)
+	}
+	const retentionInterval = time.Hour
+	go initRetention(apiCfg.DB, retentionInterval, retention)
+
 	log.Printf("Server listening on port: %v", port)
 	log.Fatal(server.ListenAndServe())
 }
@@ -1,6 +1,8 @@
 package main
 
 import (
+	"crypto/subtle"
+	"log"
 	"net/http"
 
 	"github.com/JustinLi007/rss-aggregator/internal/auth"
@@ -9,20 +11,79 @@ import (
 
 type authedHandler func(http.ResponseWriter, *http.Request, database.User)
 
-func (cfg *apiConfig) middlewareAuth(handler authedHandler) http.HandlerFunc {
+// middlewareAuth authenticates the API key of the request and rejects it
+// with 403 unless the key carries scope. Requests without an Authorization
+// header may use a session cookie instead.
+func (cfg *apiConfig) middlewareAuth(scope string, handler authedHandler) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		if cookie, err := r.Cookie(sessionCookieName); err == nil && r.Header.Get("Authorization") == "" {
+			cfg.authenticateSession(w, r, cookie.Value, scope, handler)
+			return
+		}
+
 		apiKey, err := auth.GetApiKeyToken(r.Header)
 		if err != nil {
 			respondWithError(w, http.StatusUnauthorized, err.Error())
 			return
 		}
 
-		user, err := cfg.DB.GetUserByAPIKey(r.Context(), apiKey)
+		row, err := cfg.DB.GetUserByAPIKey(r.Context(), database.GetUserByAPIKeyParams{
+			Prefix:  auth.APIKeyPrefix(apiKey),
+			KeyHash: auth.HashToken(apiKey),
+		})
 		if err != nil {
 			respondWithError(w, http.StatusNotFound, "Couldn't get user")
 			return
 		}
 
-		handler(w, r, user)
+		scopes := apiKeyScopes(row.User, row.ApiKeyScopes)
+		if !requireScope(w, scopes, scope, "API key") {
+			return
+		}
+
+		if err := cfg.DB.TouchAPIKey(r.Context(), row.ApiKeyID); err != nil {
+			log.Printf("Failed to record use of API key %v: %v", row.ApiKeyID, err)
+		}
+
+		handler(w, r.WithContext(contextWithScopes(r.Context(), scopes)), row.User)
+	}
+}
+
+// authenticateSession serves a request authenticated by session cookie.
+// Cookies are sent by the browser on its own, so state changing requests
+// must also prove they came from our client by echoing the CSRF token.
+func (cfg *apiConfig) authenticateSession(w http.ResponseWriter, r *http.Request, token, scope string, handler authedHandler) {
+	row, err := cfg.DB.GetUserBySession(r.Context(), auth.HashToken(token))
+	if err != nil {
+		clearSessionCookies(w, r)
+		respondWithError(w, http.StatusUnauthorized, "Session expired or invalid")
+		return
+	}
+
+	if !isSafeMethod(r.Method) && subtle.ConstantTimeCompare([]byte(r.Header.Get(csrfHeaderName)), []byte(row.SessionCsrfToken)) != 1 {
+		respondWithError(w, http.StatusForbidden, "Missing or invalid CSRF token")
+		return
+	}
+
+	scopes := sessionScopes(row.User)
+	if !requireScope(w, scopes, scope, "Session") {
+		return
+	}
+
+	if err := cfg.DB.TouchSession(r.Context(), row.SessionID); err != nil {
+		log.Printf("Failed to record use of session %v: %v", row.SessionID, err)
+	}
+
+	ctx := contextWithSessionID(contextWithScopes(r.Context(), scopes), row.SessionID)
+	handler(w, r.WithContext(ctx), row.User)
+}
+
+// requireScope responds with 403 and returns false unless scopes grant
+// scope. credential names what carries the scopes in the error message.
+func requireScope(w http.ResponseWriter, scopes []string, scope, credential string) bool {
+	if !hasScope(scopes, scope) {
+		respondWithError(w, http.StatusForbidden, credential+" lacks the "+scope+" scope")
+		return false
 	}
+	return true
 }
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+
+	"github.com/JustinLi007/rss-aggregator/internal/ratelimit"
+)
+
+// middlewareRateLimit refuses requests with 429 once their client address
+// has used up its attempts. A nil limiter lets every request through.
+func middlewareRateLimit(limiter *ratelimit.Limiter, handler http.HandlerFunc) http.HandlerFunc {
+	if limiter == nil {
+		return handler
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		if ok, retryAfter := limiter.Allow(clientIP(r)); !ok {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+			respondWithError(w, http.StatusTooManyRequests, "Too many requests, try again later")
+			return
+		}
+		handler(w, r)
+	}
+}
+
+// clientIP returns the address the request came from. Forwarding headers are
+// ignored since any client can set them.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -2,6 +2,8 @@ package main
 
 import (
 	"database/sql"
+	"encoding/json"
+	"fmt"
 	"time"
 
 	"github.com/JustinLi007/rss-aggregator/internal/database"
@@ -13,57 +15,211 @@ type User struct {
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 	Name      string    `json:"name"`
-	ApiKey    string    `json:"api_key"`
+	// ApiKey is only set in the response that creates the user.
+	ApiKey     string     `json:"api_key,omitempty"`
+	Role       string     `json:"role"`
+	DisabledAt *time.Time `json:"disabled_at"`
 }
 
 type Feed struct {
-	ID          uuid.UUID  `json:"id"`
-	CreatedAt   time.Time  `json:"created_at"`
-	UpdatedAt   time.Time  `json:"updated_at"`
-	Name        string     `json:"name"`
-	Url         string     `json:"url"`
-	UserID      uuid.UUID  `json:"user_id"`
-	LastFetchAt *time.Time `json:"last_fetched_at"`
+	ID           uuid.UUID  `json:"id"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+	Name         string     `json:"name"`
+	Url          string     `json:"url"`
+	UserID       uuid.UUID  `json:"user_id"`
+	LastFetchAt  *time.Time `json:"last_fetched_at"`
+	SiteUrl      *string    `json:"site_url"`
+	DormantSince *time.Time `json:"dormant_since"`
+	// Retention overrides; null means the server-wide policy applies.
+	RetentionMaxAgeDays *int32 `json:"retention_max_age_days"`
+	RetentionMaxPosts   *int32 `json:"retention_max_posts"`
 }
 
 type UsersFeedsFollow struct {
+	ID               uuid.UUID  `json:"id"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+	UserID           uuid.UUID  `json:"user_id"`
+	FeedID           uuid.UUID  `json:"feed_id"`
+	FolderID         *uuid.UUID `json:"folder_id"`
+	Title            *string    `json:"title"`
+	DisplayTitle     string     `json:"display_title,omitempty"`
+	Muted            bool       `json:"muted"`
+	Notifications    string     `json:"notifications"`
+	SortPriority     int32      `json:"sort_priority"`
+	HideFromTimeline bool       `json:"hide_from_timeline"`
+	UnreadCount      int64      `json:"unread_count"`
+}
+
+// Kinds of entries in the follow list.
+const (
+	followListKindFeed        = "feed"
+	followListKindSavedSearch = "saved_search"
+)
+
+type FollowListEntry struct {
+	Kind        string     `json:"kind"`
+	ID          uuid.UUID  `json:"id"`
+	FeedID      *uuid.UUID `json:"feed_id"`
+	FolderID    *uuid.UUID `json:"folder_id"`
+	Title       string     `json:"title"`
+	UnreadCount int64      `json:"unread_count"`
+}
+
+type Folder struct {
+	ID          uuid.UUID `json:"id"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	Name        string    `json:"name"`
+	Position    int32     `json:"position"`
+	UserID      uuid.UUID `json:"user_id"`
+	UnreadCount int64     `json:"unread_count"`
+}
+
+type SavedSearch struct {
+	ID          uuid.UUID `json:"id"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	Name        string    `json:"name"`
+	Query       string    `json:"query"`
+	UserID      uuid.UUID `json:"user_id"`
+	UnreadCount int64     `json:"unread_count"`
+}
+
+type Label struct {
+	ID          uuid.UUID `json:"id"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	Name        string    `json:"name"`
+	UserID      uuid.UUID `json:"user_id"`
+	PostCount   int64     `json:"post_count"`
+	UnreadCount int64     `json:"unread_count"`
+}
+
+type FilterRule struct {
+	ID         uuid.UUID       `json:"id"`
+	CreatedAt  time.Time       `json:"created_at"`
+	UpdatedAt  time.Time       `json:"updated_at"`
+	Name       string          `json:"name"`
+	Conditions json.RawMessage `json:"conditions"`
+	MatchAll   bool            `json:"match_all"`
+	Actions    []string        `json:"actions"`
+	Tag        *string         `json:"tag"`
+	Enabled    bool            `json:"enabled"`
+	UserID     uuid.UUID       `json:"user_id"`
+}
+
+type Notification struct {
+	ID           uuid.UUID  `json:"id"`
+	CreatedAt    time.Time  `json:"created_at"`
+	FilterRuleID *uuid.UUID `json:"filter_rule_id"`
+	Post         Post       `json:"post"`
+}
+
+type APIKey struct {
 	ID        uuid.UUID `json:"id"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
-	UserID    uuid.UUID `json:"user_id"`
-	FeedID    uuid.UUID `json:"feed_id"`
+	Name      string    `json:"name"`
+	// Key is only set in the responses that create a key.
+	Key        string     `json:"key,omitempty"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+}
+
+type Session struct {
+	ID         uuid.UUID `json:"id"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	UserAgent  *string   `json:"user_agent"`
+	// Current marks the session the listing request was made with.
+	Current bool `json:"current"`
+}
+
+type Invite struct {
+	ID        uuid.UUID  `json:"id"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	Code      string     `json:"code"`
+	MaxUses   int32      `json:"max_uses"`
+	Uses      int32      `json:"uses"`
+	ExpiresAt *time.Time `json:"expires_at"`
+	RevokedAt *time.Time `json:"revoked_at"`
+	CreatedBy *uuid.UUID `json:"created_by"`
+}
+
+type OutputFeedToken struct {
+	ID        uuid.UUID         `json:"id"`
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
+	Name      string            `json:"name"`
+	Token     string            `json:"token"`
+	URLs      map[string]string `json:"urls"`
 }
 
 type Post struct {
-	ID          uuid.UUID `json:"id"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
-	Title       string    `json:"title"`
-	Url         string    `json:"url"`
-	Description *string   `json:"description"`
-	PublishedAt time.Time `json:"published_at"`
-	FeedID      uuid.UUID `json:"feed_id"`
+	ID          uuid.UUID  `json:"id"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	Title       string     `json:"title"`
+	Url         string     `json:"url"`
+	Description *string    `json:"description"`
+	PublishedAt time.Time  `json:"published_at"`
+	FeedID      *uuid.UUID `json:"feed_id"`
+	Content     *string    `json:"content"`
+	Author      *string    `json:"author"`
+	Categories  []string   `json:"categories"`
+}
+
+type SearchResult struct {
+	Post Post    `json:"post"`
+	Rank float32 `json:"rank"`
+	// Snippet is HTML: the matching text with tags stripped and escaped, so
+	// the <mark> elements around matches are its only markup.
+	Snippet string `json:"snippet"`
+}
+
+type PostsPage struct {
+	Posts      []Post  `json:"posts"`
+	NextCursor *string `json:"next_cursor"`
 }
 
 func databaseUserToUser(user database.User) User {
 	return User{
-		ID:        user.ID,
-		CreatedAt: user.CreatedAt,
-		UpdatedAt: user.UpdatedAt,
-		Name:      user.Name,
-		ApiKey:    user.ApiKey,
+		ID:         user.ID,
+		CreatedAt:  user.CreatedAt,
+		UpdatedAt:  user.UpdatedAt,
+		Name:       user.Name,
+		Role:       user.Role,
+		DisabledAt: nullTimeToTimePtr(user.DisabledAt),
 	}
 }
 
+func databaseUsersToUsers(users []database.User) []User {
+	result := make([]User, len(users))
+	for i, v := range users {
+		result[i] = databaseUserToUser(v)
+	}
+	return result
+}
+
 func databaseFeedToFeed(feed database.Feed) Feed {
 	return Feed{
-		ID:          feed.ID,
-		CreatedAt:   feed.CreatedAt,
-		UpdatedAt:   feed.CreatedAt,
-		Name:        feed.Name,
-		Url:         feed.Url,
-		UserID:      feed.UserID,
-		LastFetchAt: nullTimeToTimePtr(feed.LastFetchedAt),
+		ID:                  feed.ID,
+		CreatedAt:           feed.CreatedAt,
+		UpdatedAt:           feed.CreatedAt,
+		Name:                feed.Name,
+		Url:                 feed.Url,
+		UserID:              feed.UserID,
+		LastFetchAt:         nullTimeToTimePtr(feed.LastFetchedAt),
+		SiteUrl:             nullStringToStringPtr(feed.SiteUrl),
+		DormantSince:        nullTimeToTimePtr(feed.DormantSince),
+		RetentionMaxAgeDays: nullInt32ToInt32Ptr(feed.RetentionMaxAgeDays),
+		RetentionMaxPosts:   nullInt32ToInt32Ptr(feed.RetentionMaxPosts),
 	}
 }
 
@@ -77,18 +233,224 @@ func databaseFeedsToFeeds(feeds []database.Feed) []Feed {
 
 func databaseFeedFollowToFeedFollow(feedFollow database.UsersFeedsFollow) UsersFeedsFollow {
 	return UsersFeedsFollow{
-		ID:        feedFollow.ID,
-		CreatedAt: feedFollow.CreatedAt,
-		UpdatedAt: feedFollow.UpdatedAt,
-		UserID:    feedFollow.UserID,
-		FeedID:    feedFollow.FeedID,
+		ID:               feedFollow.ID,
+		CreatedAt:        feedFollow.CreatedAt,
+		UpdatedAt:        feedFollow.UpdatedAt,
+		UserID:           feedFollow.UserID,
+		FeedID:           feedFollow.FeedID,
+		FolderID:         nullUUIDToUUIDPtr(feedFollow.FolderID),
+		Title:            nullStringToStringPtr(feedFollow.Title),
+		Muted:            feedFollow.Muted,
+		Notifications:    feedFollow.Notifications,
+		SortPriority:     feedFollow.SortPriority,
+		HideFromTimeline: feedFollow.HideFromTimeline,
 	}
 }
 
-func databaseFeedFollowsToFeedFollows(feedFollows []database.UsersFeedsFollow) []UsersFeedsFollow {
+func databaseFeedFollowRowsToFeedFollows(feedFollows []database.GetFeedFollowsRow) []UsersFeedsFollow {
 	result := make([]UsersFeedsFollow, len(feedFollows))
 	for i, v := range feedFollows {
-		result[i] = databaseFeedFollowToFeedFollow(v)
+		result[i] = databaseFeedFollowToFeedFollow(v.UsersFeedsFollow)
+		result[i].DisplayTitle = v.FeedName
+		if v.UsersFeedsFollow.Title.Valid {
+			result[i].DisplayTitle = v.UsersFeedsFollow.Title.String
+		}
+	}
+	return result
+}
+
+func feedFollowToFollowListEntry(feedFollow UsersFeedsFollow) FollowListEntry {
+	return FollowListEntry{
+		Kind:        followListKindFeed,
+		ID:          feedFollow.ID,
+		FeedID:      &feedFollow.FeedID,
+		FolderID:    feedFollow.FolderID,
+		Title:       feedFollow.DisplayTitle,
+		UnreadCount: feedFollow.UnreadCount,
+	}
+}
+
+func savedSearchToFollowListEntry(savedSearch SavedSearch) FollowListEntry {
+	return FollowListEntry{
+		Kind:        followListKindSavedSearch,
+		ID:          savedSearch.ID,
+		Title:       savedSearch.Name,
+		UnreadCount: savedSearch.UnreadCount,
+	}
+}
+
+func databaseFolderToFolder(folder database.Folder) Folder {
+	return Folder{
+		ID:        folder.ID,
+		CreatedAt: folder.CreatedAt,
+		UpdatedAt: folder.UpdatedAt,
+		Name:      folder.Name,
+		Position:  folder.Position,
+		UserID:    folder.UserID,
+	}
+}
+
+func databaseFoldersToFolders(folders []database.Folder) []Folder {
+	result := make([]Folder, len(folders))
+	for i, v := range folders {
+		result[i] = databaseFolderToFolder(v)
+	}
+	return result
+}
+
+func databaseSavedSearchToSavedSearch(savedSearch database.SavedSearch) SavedSearch {
+	return SavedSearch{
+		ID:        savedSearch.ID,
+		CreatedAt: savedSearch.CreatedAt,
+		UpdatedAt: savedSearch.UpdatedAt,
+		Name:      savedSearch.Name,
+		Query:     savedSearch.Query,
+		UserID:    savedSearch.UserID,
+	}
+}
+
+func databaseSavedSearchesToSavedSearches(savedSearches []database.SavedSearch) []SavedSearch {
+	result := make([]SavedSearch, len(savedSearches))
+	for i, v := range savedSearches {
+		result[i] = databaseSavedSearchToSavedSearch(v)
+	}
+	return result
+}
+
+func databaseLabelToLabel(label database.Label) Label {
+	return Label{
+		ID:        label.ID,
+		CreatedAt: label.CreatedAt,
+		UpdatedAt: label.UpdatedAt,
+		Name:      label.Name,
+		UserID:    label.UserID,
+	}
+}
+
+func databaseLabelRowsToLabels(labels []database.GetLabelsByUserRow) []Label {
+	result := make([]Label, len(labels))
+	for i, v := range labels {
+		result[i] = databaseLabelToLabel(v.Label)
+		result[i].PostCount = v.PostCount
+		result[i].UnreadCount = v.UnreadCount
+	}
+	return result
+}
+
+func databaseFilterRuleToFilterRule(rule database.FilterRule) FilterRule {
+	return FilterRule{
+		ID:         rule.ID,
+		CreatedAt:  rule.CreatedAt,
+		UpdatedAt:  rule.UpdatedAt,
+		Name:       rule.Name,
+		Conditions: rule.Conditions,
+		MatchAll:   rule.MatchAll,
+		Actions:    rule.Actions,
+		Tag:        nullStringToStringPtr(rule.Tag),
+		Enabled:    rule.Enabled,
+		UserID:     rule.UserID,
+	}
+}
+
+func databaseFilterRulesToFilterRules(filterRules []database.FilterRule) []FilterRule {
+	result := make([]FilterRule, len(filterRules))
+	for i, v := range filterRules {
+		result[i] = databaseFilterRuleToFilterRule(v)
+	}
+	return result
+}
+
+func databaseNotificationRowsToNotifications(notifications []database.GetNotificationsByUserRow) []Notification {
+	result := make([]Notification, len(notifications))
+	for i, v := range notifications {
+		result[i] = Notification{
+			ID:           v.Notification.ID,
+			CreatedAt:    v.Notification.CreatedAt,
+			FilterRuleID: nullUUIDToUUIDPtr(v.Notification.FilterRuleID),
+			Post:         databasePostToPost(v.Post),
+		}
+	}
+	return result
+}
+
+func databaseAPIKeyToAPIKey(apiKey database.ApiKey) APIKey {
+	return APIKey{
+		ID:         apiKey.ID,
+		CreatedAt:  apiKey.CreatedAt,
+		UpdatedAt:  apiKey.UpdatedAt,
+		Name:       apiKey.Name,
+		Prefix:     apiKey.Prefix,
+		Scopes:     apiKey.Scopes,
+		LastUsedAt: nullTimeToTimePtr(apiKey.LastUsedAt),
+		ExpiresAt:  nullTimeToTimePtr(apiKey.ExpiresAt),
+	}
+}
+
+func databaseAPIKeysToAPIKeys(apiKeys []database.ApiKey) []APIKey {
+	result := make([]APIKey, len(apiKeys))
+	for i, v := range apiKeys {
+		result[i] = databaseAPIKeyToAPIKey(v)
+	}
+	return result
+}
+
+func databaseSessionsToSessions(sessions []database.Session, currentID uuid.UUID) []Session {
+	result := make([]Session, len(sessions))
+	for i, v := range sessions {
+		result[i] = Session{
+			ID:         v.ID,
+			CreatedAt:  v.CreatedAt,
+			LastSeenAt: v.LastSeenAt,
+			ExpiresAt:  v.ExpiresAt,
+			UserAgent:  nullStringToStringPtr(v.UserAgent),
+			Current:    v.ID == currentID,
+		}
+	}
+	return result
+}
+
+func databaseInviteToInvite(invite database.Invite) Invite {
+	return Invite{
+		ID:        invite.ID,
+		CreatedAt: invite.CreatedAt,
+		UpdatedAt: invite.UpdatedAt,
+		Code:      invite.Code,
+		MaxUses:   invite.MaxUses,
+		Uses:      invite.Uses,
+		ExpiresAt: nullTimeToTimePtr(invite.ExpiresAt),
+		RevokedAt: nullTimeToTimePtr(invite.RevokedAt),
+		CreatedBy: nullUUIDToUUIDPtr(invite.CreatedBy),
+	}
+}
+
+func databaseInvitesToInvites(invites []database.Invite) []Invite {
+	result := make([]Invite, len(invites))
+	for i, v := range invites {
+		result[i] = databaseInviteToInvite(v)
+	}
+	return result
+}
+
+func databaseOutputFeedTokenToOutputFeedToken(token database.OutputFeedToken, baseURL string) OutputFeedToken {
+	urls := make(map[string]string, len(outputFeedFormats))
+	for _, format := range outputFeedFormats {
+		urls[format] = fmt.Sprintf("%v/v1/output/%v/%v", baseURL, token.Token, format)
+	}
+
+	return OutputFeedToken{
+		ID:        token.ID,
+		CreatedAt: token.CreatedAt,
+		UpdatedAt: token.UpdatedAt,
+		Name:      token.Name,
+		Token:     token.Token,
+		URLs:      urls,
+	}
+}
+
+func databaseOutputFeedTokensToOutputFeedTokens(tokens []database.OutputFeedToken, baseURL string) []OutputFeedToken {
+	result := make([]OutputFeedToken, len(tokens))
+	for i, v := range tokens {
+		result[i] = databaseOutputFeedTokenToOutputFeedToken(v, baseURL)
 	}
 	return result
 }
@@ -102,7 +464,10 @@ func databasePostToPost(post database.Post) Post {
 		Url:         post.Url,
 		Description: nullStringToStringPtr(post.Description),
 		PublishedAt: post.PublishedAt,
-		FeedID:      post.FeedID,
+		FeedID:      nullUUIDToUUIDPtr(post.FeedID),
+		Content:     nullStringToStringPtr(post.Content),
+		Author:      nullStringToStringPtr(post.Author),
+		Categories:  post.Categories,
 	}
 }
 
@@ -127,3 +492,17 @@ func nullStringToStringPtr(s sql.NullString) *string {
 	}
 	return nil
 }
+
+func nullUUIDToUUIDPtr(id uuid.NullUUID) *uuid.UUID {
+	if id.Valid {
+		return &id.UUID
+	}
+	return nil
+}
+
+func nullInt32ToInt32Ptr(i sql.NullInt32) *int32 {
+	if i.Valid {
+		return &i.Int32
+	}
+	return nil
+}
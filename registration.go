@@ -0,0 +1,51 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Registration modes decide who may sign up through POST /v1/users.
+const (
+	registrationOpen   = "open"
+	registrationInvite = "invite"
+	registrationClosed = "closed"
+)
+
+const (
+	maxUserNameLength = 64
+
+	defaultRegistrationRateLimit = 10
+	registrationRateWindow       = time.Hour
+	loginRateLimit               = 10
+	loginRateWindow              = time.Minute
+)
+
+// loadRegistrationMode reads REGISTRATION_MODE, which defaults to open.
+func loadRegistrationMode() (string, error) {
+	mode := os.Getenv("REGISTRATION_MODE")
+	switch mode {
+	case "":
+		return registrationOpen, nil
+	case registrationOpen, registrationInvite, registrationClosed:
+		return mode, nil
+	}
+	return "", fmt.Errorf("Invalid REGISTRATION_MODE %q; use open, invite or closed", mode)
+}
+
+// loadRegistrationRateLimit reads REGISTRATION_RATE_LIMIT, the number of
+// sign ups allowed per client address and hour. 0 turns the limit off.
+func loadRegistrationRateLimit() (int, error) {
+	value := os.Getenv("REGISTRATION_RATE_LIMIT")
+	if value == "" {
+		return defaultRegistrationRateLimit, nil
+	}
+
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit < 0 {
+		return 0, fmt.Errorf("Invalid REGISTRATION_RATE_LIMIT %q", value)
+	}
+	return limit, nil
+}
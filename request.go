@@ -0,0 +1,138 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// parseUUIDQueryValues collects every UUID passed through the query parameter
+// key, accepting both repeated parameters and comma separated lists.
+func parseUUIDQueryValues(r *http.Request, key string) ([]uuid.UUID, error) {
+	var result []uuid.UUID
+	for _, value := range r.URL.Query()[key] {
+		for _, v := range strings.Split(value, ",") {
+			v = strings.TrimSpace(v)
+			if v == "" {
+				continue
+			}
+
+			id, err := uuid.Parse(v)
+			if err != nil {
+				return nil, errors.New("Invalid " + key)
+			}
+			result = append(result, id)
+		}
+	}
+	return result, nil
+}
+
+// parseNullUUIDQueryValue reads an optional UUID from the query parameter key.
+func parseNullUUIDQueryValue(r *http.Request, key string) (uuid.NullUUID, error) {
+	value := r.URL.Query().Get(key)
+	if value == "" {
+		return uuid.NullUUID{}, nil
+	}
+
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return uuid.NullUUID{}, errors.New("Invalid " + key)
+	}
+	return uuid.NullUUID{UUID: id, Valid: true}, nil
+}
+
+// parseLimitQueryValue reads the limit query parameter, falling back to
+// defaultLimit and capping the result at maxLimit.
+func parseLimitQueryValue(r *http.Request, defaultLimit, maxLimit int) (int, error) {
+	value := r.URL.Query().Get("limit")
+	if value == "" {
+		return defaultLimit, nil
+	}
+
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit <= 0 {
+		return 0, errors.New("Invalid limit")
+	}
+	return min(limit, maxLimit), nil
+}
+
+// parseBoolQueryValue reads the boolean query parameter key, treating a
+// missing value as false.
+func parseBoolQueryValue(r *http.Request, key string) (bool, error) {
+	value := r.URL.Query().Get(key)
+	if value == "" {
+		return false, nil
+	}
+
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, errors.New("Invalid " + key)
+	}
+	return b, nil
+}
+
+// parseNullBoolQueryValue reads an optional boolean from the query parameter
+// key.
+func parseNullBoolQueryValue(r *http.Request, key string) (sql.NullBool, error) {
+	value := r.URL.Query().Get(key)
+	if value == "" {
+		return sql.NullBool{}, nil
+	}
+
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return sql.NullBool{}, errors.New("Invalid " + key)
+	}
+	return sql.NullBool{Bool: b, Valid: true}, nil
+}
+
+// parseTimeQueryValue reads an RFC 3339 timestamp or a YYYY-MM-DD date from
+// the query parameter key.
+func parseTimeQueryValue(r *http.Request, key string) (sql.NullTime, error) {
+	value := r.URL.Query().Get(key)
+	if value == "" {
+		return sql.NullTime{}, nil
+	}
+
+	for _, layout := range []string{time.RFC3339, time.DateOnly} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return sql.NullTime{
+				Time:  t.UTC(),
+				Valid: true,
+			}, nil
+		}
+	}
+	return sql.NullTime{}, errors.New("Invalid " + key)
+}
+
+// parseUUIDPathValue reads the UUID stored in the path wildcard key.
+func parseUUIDPathValue(r *http.Request, key string) (uuid.UUID, error) {
+	value := r.PathValue(key)
+	if value == "" {
+		return uuid.Nil, errors.New("No " + key + " included")
+	}
+
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return uuid.Nil, errors.New("Invalid " + key)
+	}
+	return id, nil
+}
+
+// requestBaseURL returns the scheme and host the client used to reach the
+// server, honouring X-Forwarded-Proto when running behind a proxy.
+func requestBaseURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	if proto := r.Header.Get("X-Forwarded-Proto"); proto != "" {
+		scheme = proto
+	}
+	return scheme + "://" + r.Host
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"expvar"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/JustinLi007/rss-aggregator/internal/database"
+)
+
+// retentionBatchSize bounds every DELETE so a pruning run never holds locks
+// on a large part of the posts table at once.
+const (
+	retentionBatchSize  = 500
+	retentionBatchPause = 100 * time.Millisecond
+)
+
+// prunedPostsHorizonDays is how long the URLs of deleted posts are kept to
+// stop the scraper from adding them again. Feeds only list their latest
+// items, so past this a pruned item has long dropped out of its feed.
+const prunedPostsHorizonDays = 90
+
+// retentionMetrics is published on /debug/vars.
+var retentionMetrics = expvar.NewMap("post_retention")
+
+// retentionPolicy is the server-wide retention policy. Feeds can override
+// the max age and max posts; a zero value disables that limit.
+type retentionPolicy struct {
+	MaxAgeDays int32
+	MaxPosts   int32
+	// Posts younger than UnreadWindowDays are kept while one of their
+	// feed's followers hasn't read them.
+	UnreadWindowDays int32
+}
+
+// loadRetentionPolicy reads POST_RETENTION_MAX_AGE_DAYS,
+// POST_RETENTION_MAX_POSTS_PER_FEED and POST_RETENTION_UNREAD_WINDOW_DAYS.
+func loadRetentionPolicy() (retentionPolicy, error) {
+	policy := retentionPolicy{UnreadWindowDays: 7}
+	for name, target := range map[string]*int32{
+		"POST_RETENTION_MAX_AGE_DAYS":       &policy.MaxAgeDays,
+		"POST_RETENTION_MAX_POSTS_PER_FEED": &policy.MaxPosts,
+		"POST_RETENTION_UNREAD_WINDOW_DAYS": &policy.UnreadWindowDays,
+	} {
+		value := os.Getenv(name)
+		if value == "" {
+			continue
+		}
+		n, err := strconv.ParseInt(value, 10, 32)
+		if err != nil || n < 0 {
+			return retentionPolicy{}, fmt.Errorf("Invalid %v: %q", name, value)
+		}
+		*target = int32(n)
+	}
+	return policy, nil
+}
+
+func initRetention(db *database.Queries, interval time.Duration, policy retentionPolicy) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for ; ; <-ticker.C {
+		prunePosts(context.Background(), db, policy)
+	}
+}
+
+// prunePosts deletes the posts the retention policies no longer cover, in
+// batches. Starred and labelled posts are never deleted.
+func prunePosts(ctx context.Context, db *database.Queries, policy retentionPolicy) {
+	maxAgeDays := sql.NullInt32{Int32: policy.MaxAgeDays, Valid: policy.MaxAgeDays > 0}
+	maxPosts := sql.NullInt32{Int32: policy.MaxPosts, Valid: policy.MaxPosts > 0}
+
+	expired := pruneInBatches(func() (int64, error) {
+		return db.PruneExpiredPosts(ctx, database.PruneExpiredPostsParams{
+			DefaultMaxAgeDays: maxAgeDays,
+			UnreadWindowDays:  policy.UnreadWindowDays,
+			BatchSize:         retentionBatchSize,
+		})
+	})
+	excess := pruneInBatches(func() (int64, error) {
+		return db.PruneExcessPosts(ctx, database.PruneExcessPostsParams{
+			DefaultMaxPosts:  maxPosts,
+			UnreadWindowDays: policy.UnreadWindowDays,
+			BatchSize:        retentionBatchSize,
+		})
+	})
+	detached := pruneInBatches(func() (int64, error) {
+		return db.PruneDetachedPosts(ctx, retentionBatchSize)
+	})
+	forgotten := pruneInBatches(func() (int64, error) {
+		return db.ForgetPrunedPosts(ctx, database.ForgetPrunedPostsParams{
+			HorizonDays: prunedPostsHorizonDays,
+			BatchSize:   retentionBatchSize,
+		})
+	})
+
+	retentionMetrics.Add("runs", 1)
+	retentionMetrics.Add("expired_posts_deleted", expired)
+	retentionMetrics.Add("excess_posts_deleted", excess)
+	retentionMetrics.Add("detached_posts_deleted", detached)
+	retentionMetrics.Add("pruned_posts_forgotten", forgotten)
+	lastRun := new(expvar.String)
+	lastRun.Set(time.Now().UTC().Format(time.RFC3339))
+	retentionMetrics.Set("last_run", lastRun)
+
+	if expired > 0 || excess > 0 || detached > 0 {
+		log.Printf("Retention: %v expired, %v excess and %v detached posts deleted", expired, excess, detached)
+	}
+}
+
+// pruneInBatches calls prune until it deletes less than a full batch and
+// returns the total number of rows deleted.
+func pruneInBatches(prune func() (int64, error)) int64 {
+	var total int64
+	for {
+		deleted, err := prune()
+		if err != nil {
+			log.Printf("Failed to prune posts: %v", err)
+			return total
+		}
+		total += deleted
+		if deleted < retentionBatchSize {
+			return total
+		}
+		time.Sleep(retentionBatchPause)
+	}
+}
@@ -0,0 +1,315 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/JustinLi007/rss-aggregator/internal/database"
+	"github.com/google/uuid"
+)
+
+func TestPruneInBatches(t *testing.T) {
+	errPrune := errors.New("prune failed")
+
+	tests := []struct {
+		batches       []int64
+		err           error
+		expectedTotal int64
+		expectedCalls int
+	}{
+		{[]int64{3}, nil, 3, 1},
+		{[]int64{0}, nil, 0, 1},
+		{[]int64{retentionBatchSize, retentionBatchSize, 10}, nil, 2*retentionBatchSize + 10, 3},
+		{[]int64{retentionBatchSize, 0}, nil, retentionBatchSize, 2},
+		// The error comes after the batches run out.
+		{[]int64{retentionBatchSize}, errPrune, retentionBatchSize, 2},
+	}
+
+	for _, test := range tests {
+		calls := 0
+		total := pruneInBatches(func() (int64, error) {
+			calls++
+			if calls > len(test.batches) {
+				return 0, test.err
+			}
+			return test.batches[calls-1], nil
+		})
+		if total != test.expectedTotal {
+			t.Errorf("pruneInBatches(%v): expected %v posts deleted, got %v", test.batches, test.expectedTotal, total)
+		}
+		if calls != test.expectedCalls {
+			t.Errorf("pruneInBatches(%v): expected %v calls, got %v", test.batches, test.expectedCalls, calls)
+		}
+	}
+}
+
+// testDB connects to the migrated database at PSQL_CONNECTION_URL
+// and skips the test when it isn't set.
+func testDB(t *testing.T) (*sql.DB, *database.Queries) {
+	t.Helper()
+
+	dbURL := os.Getenv("PSQL_CONNECTION_URL")
+	if dbURL == "" {
+		t.Skip("PSQL_CONNECTION_URL is not set")
+	}
+	conn, err := sql.Open("postgres", dbURL)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn, database.New(conn)
+}
+
+// createTestFeed creates a user following a new feed. Both are deleted when
+// the test ends.
+func createTestFeed(t *testing.T, db *database.Queries) (database.User, database.Feed) {
+	t.Helper()
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	user, err := db.CreateUser(ctx, database.CreateUserParams{
+		ID:        uuid.New(),
+		CreatedAt: now,
+		UpdatedAt: now,
+		Name:      fmt.Sprintf("Test User %v", now.UnixNano()),
+	})
+	if err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	t.Cleanup(func() { db.DeleteUser(ctx, user.ID) })
+
+	url := fmt.Sprintf("https://example.com/%v.xml", uuid.New())
+	feed, err := db.CreateFeed(ctx, database.CreateFeedParams{
+		ID:           uuid.New(),
+		CreatedAt:    now,
+		UpdatedAt:    now,
+		Name:         "Test Feed",
+		Url:          url,
+		UserID:       user.ID,
+		CanonicalUrl: url,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create feed: %v", err)
+	}
+	t.Cleanup(func() { db.DeleteFeed(ctx, feed.ID) })
+
+	if _, err := db.FollowFeed(ctx, database.FollowFeedParams{
+		ID:        uuid.New(),
+		CreatedAt: now,
+		UpdatedAt: now,
+		UserID:    user.ID,
+		FeedID:    feed.ID,
+	}); err != nil {
+		t.Fatalf("Failed to follow feed: %v", err)
+	}
+	return user, feed
+}
+
+func setTestFeedRetention(t *testing.T, db *database.Queries, feed database.Feed, maxAgeDays, maxPosts sql.NullInt32) {
+	t.Helper()
+
+	if _, err := db.SetFeedRetention(context.Background(), database.SetFeedRetentionParams{
+		ID:                  feed.ID,
+		RetentionMaxAgeDays: maxAgeDays,
+		RetentionMaxPosts:   maxPosts,
+	}); err != nil {
+		t.Fatalf("Failed to set retention: %v", err)
+	}
+}
+
+// createTestPosts creates a post of feed per age, in the same order.
+func createTestPosts(t *testing.T, db *database.Queries, feed database.Feed, ages ...time.Duration) []database.Post {
+	t.Helper()
+
+	posts := make([]database.Post, len(ages))
+	for i, age := range ages {
+		post, err := db.CreatePost(context.Background(), database.CreatePostParams{
+			ID:          uuid.New(),
+			CreatedAt:   time.Now().UTC(),
+			UpdatedAt:   time.Now().UTC(),
+			Title:       fmt.Sprintf("Post %v", i),
+			Url:         fmt.Sprintf("https://example.com/%v", uuid.New()),
+			PublishedAt: time.Now().UTC().Add(-age),
+			FeedID:      uuid.NullUUID{UUID: feed.ID, Valid: true},
+			Categories:  []string{},
+		})
+		if err != nil {
+			t.Fatalf("Failed to create post: %v", err)
+		}
+		posts[i] = post
+	}
+	return posts
+}
+
+func markTestPostsRead(t *testing.T, db *database.Queries, user database.User, posts ...database.Post) {
+	t.Helper()
+
+	ids := make([]uuid.UUID, len(posts))
+	for i, post := range posts {
+		ids[i] = post.ID
+	}
+	if _, err := db.MarkPostsRead(context.Background(), database.MarkPostsReadParams{
+		UserID:  user.ID,
+		PostIds: ids,
+	}); err != nil {
+		t.Fatalf("Failed to mark posts read: %v", err)
+	}
+}
+
+func starTestPost(t *testing.T, db *database.Queries, user database.User, post database.Post) {
+	t.Helper()
+
+	if _, err := db.StarPost(context.Background(), database.StarPostParams{
+		UserID: user.ID,
+		PostID: post.ID,
+	}); err != nil {
+		t.Fatalf("Failed to star post: %v", err)
+	}
+}
+
+// remainingPosts returns which of posts still exist.
+func remainingPosts(t *testing.T, conn *sql.DB, posts []database.Post) []bool {
+	t.Helper()
+
+	result := make([]bool, len(posts))
+	for i, post := range posts {
+		if err := conn.QueryRow("SELECT EXISTS (SELECT 1 FROM posts WHERE id = $1)", post.ID).Scan(&result[i]); err != nil {
+			t.Fatalf("Failed to look up post: %v", err)
+		}
+	}
+	return result
+}
+
+// prunedURLs returns the URLs of posts recorded as pruned from feed.
+func prunedURLs(t *testing.T, db *database.Queries, feed database.Feed, posts []database.Post) []string {
+	t.Helper()
+
+	urls := make([]string, len(posts))
+	for i, post := range posts {
+		urls[i] = post.Url
+	}
+	result, err := db.GetPrunedPostURLs(context.Background(), database.GetPrunedPostURLsParams{
+		FeedID: feed.ID,
+		Urls:   urls,
+	})
+	if err != nil {
+		t.Fatalf("Failed to retrieve pruned posts: %v", err)
+	}
+	slices.Sort(result)
+	return result
+}
+
+func TestPruneExpiredPosts(t *testing.T) {
+	conn, db := testDB(t)
+	user, feed := createTestFeed(t, db)
+	setTestFeedRetention(t, db, feed, sql.NullInt32{Int32: 30, Valid: true}, sql.NullInt32{})
+
+	day := 24 * time.Hour
+	// Expired and read, expired and starred, expired but unread within the
+	// unread window, and recent.
+	posts := createTestPosts(t, db, feed, 60*day, 60*day, 40*day, day)
+	markTestPostsRead(t, db, user, posts[0], posts[1])
+	starTestPost(t, db, user, posts[1])
+
+	if _, err := db.PruneExpiredPosts(context.Background(), database.PruneExpiredPostsParams{
+		UnreadWindowDays: 50,
+		BatchSize:        retentionBatchSize,
+	}); err != nil {
+		t.Fatalf("Failed to prune posts: %v", err)
+	}
+
+	if actual, expected := remainingPosts(t, conn, posts), []bool{false, true, true, true}; !slices.Equal(actual, expected) {
+		t.Errorf("Expected remaining posts %v, got %v", expected, actual)
+	}
+	if actual, expected := prunedURLs(t, db, feed, posts), []string{posts[0].Url}; !slices.Equal(actual, expected) {
+		t.Errorf("Expected pruned urls %v, got %v", expected, actual)
+	}
+}
+
+func TestPruneExcessPosts(t *testing.T) {
+	conn, db := testDB(t)
+	user, feed := createTestFeed(t, db)
+	setTestFeedRetention(t, db, feed, sql.NullInt32{}, sql.NullInt32{Int32: 2, Valid: true})
+
+	hour := time.Hour
+	// Newest first: the first two are within the limit, the oldest is starred.
+	posts := createTestPosts(t, db, feed, hour, 2*hour, 3*hour, 4*hour)
+	markTestPostsRead(t, db, user, posts...)
+	starTestPost(t, db, user, posts[3])
+
+	if _, err := db.PruneExcessPosts(context.Background(), database.PruneExcessPostsParams{
+		UnreadWindowDays: 7,
+		BatchSize:        retentionBatchSize,
+	}); err != nil {
+		t.Fatalf("Failed to prune posts: %v", err)
+	}
+
+	if actual, expected := remainingPosts(t, conn, posts), []bool{true, true, false, true}; !slices.Equal(actual, expected) {
+		t.Errorf("Expected remaining posts %v, got %v", expected, actual)
+	}
+	if actual, expected := prunedURLs(t, db, feed, posts), []string{posts[2].Url}; !slices.Equal(actual, expected) {
+		t.Errorf("Expected pruned urls %v, got %v", expected, actual)
+	}
+}
+
+func TestPruneDetachedPosts(t *testing.T) {
+	conn, db := testDB(t)
+	user, feed := createTestFeed(t, db)
+
+	// Starred posts survive their feed; once unstarred, retention removes them.
+	posts := createTestPosts(t, db, feed, time.Hour, 2*time.Hour)
+	// The post still starred outlives the feed and the user.
+	t.Cleanup(func() { conn.Exec("DELETE FROM posts WHERE id = $1", posts[1].ID) })
+	starTestPost(t, db, user, posts[0])
+	starTestPost(t, db, user, posts[1])
+
+	if _, err := db.DeleteFeed(context.Background(), feed.ID); err != nil {
+		t.Fatalf("Failed to delete feed: %v", err)
+	}
+	if _, err := db.UnstarPost(context.Background(), database.UnstarPostParams{
+		UserID: user.ID,
+		PostID: posts[0].ID,
+	}); err != nil {
+		t.Fatalf("Failed to unstar post: %v", err)
+	}
+	if _, err := db.PruneDetachedPosts(context.Background(), retentionBatchSize); err != nil {
+		t.Fatalf("Failed to prune posts: %v", err)
+	}
+
+	if actual, expected := remainingPosts(t, conn, posts), []bool{false, true}; !slices.Equal(actual, expected) {
+		t.Errorf("Expected remaining posts %v, got %v", expected, actual)
+	}
+}
+
+func TestForgetPrunedPosts(t *testing.T) {
+	conn, db := testDB(t)
+	_, feed := createTestFeed(t, db)
+
+	day := 24 * time.Hour
+	posts := []database.Post{
+		{Url: fmt.Sprintf("https://example.com/%v", uuid.New())},
+		{Url: fmt.Sprintf("https://example.com/%v", uuid.New())},
+	}
+	for i, age := range []time.Duration{(prunedPostsHorizonDays + 1) * day, day} {
+		if _, err := conn.Exec("INSERT INTO pruned_posts(feed_id, url, pruned_at) VALUES($1, $2, $3)", feed.ID, posts[i].Url, time.Now().UTC().Add(-age)); err != nil {
+			t.Fatalf("Failed to record pruned post: %v", err)
+		}
+	}
+
+	if _, err := db.ForgetPrunedPosts(context.Background(), database.ForgetPrunedPostsParams{
+		HorizonDays: prunedPostsHorizonDays,
+		BatchSize:   retentionBatchSize,
+	}); err != nil {
+		t.Fatalf("Failed to forget pruned posts: %v", err)
+	}
+
+	if actual, expected := prunedURLs(t, db, feed, posts), []string{posts[1].Url}; !slices.Equal(actual, expected) {
+		t.Errorf("Expected pruned urls %v, got %v", expected, actual)
+	}
+}
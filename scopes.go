@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/JustinLi007/rss-aggregator/internal/database"
+)
+
+// API key scopes. scopeNone marks routes any valid key may call.
+const (
+	scopeNone         = ""
+	scopePostsRead    = "posts:read"
+	scopePostsWrite   = "posts:write"
+	scopeFeedsWrite   = "feeds:write"
+	scopeFollowsRead  = "follows:read"
+	scopeFollowsWrite = "follows:write"
+	scopeAccountWrite = "account:write"
+	scopeAdmin        = "admin"
+)
+
+// userScopes are the scopes of a key created without an explicit list.
+var userScopes = []string{
+	scopePostsRead,
+	scopePostsWrite,
+	scopeFeedsWrite,
+	scopeFollowsRead,
+	scopeFollowsWrite,
+	scopeAccountWrite,
+}
+
+type scopesContextKey struct{}
+
+func contextWithScopes(ctx context.Context, scopes []string) context.Context {
+	return context.WithValue(ctx, scopesContextKey{}, scopes)
+}
+
+// scopesFromContext returns the scopes granted to the current request.
+func scopesFromContext(ctx context.Context) []string {
+	scopes, _ := ctx.Value(scopesContextKey{}).([]string)
+	return scopes
+}
+
+// hasScope reports whether scopes grant scope. The admin scope grants every
+// other scope.
+func hasScope(scopes []string, scope string) bool {
+	return scope == scopeNone || slices.Contains(scopes, scope) || slices.Contains(scopes, scopeAdmin)
+}
+
+// apiKeyScopes returns the scopes an API key of user grants. A key keeps its
+// admin scope only while its user is an admin.
+func apiKeyScopes(user database.User, scopes []string) []string {
+	if user.Role == roleAdmin {
+		return scopes
+	}
+	return slices.DeleteFunc(slices.Clone(scopes), func(s string) bool { return s == scopeAdmin })
+}
+
+// validateScopes checks that user may hand out scopes from a request that was
+// granted granted: only known scopes, admin only for admins, and never more
+// than the request itself holds.
+func validateScopes(user database.User, granted, scopes []string) error {
+	for _, scope := range scopes {
+		if scope != scopeAdmin && !slices.Contains(userScopes, scope) {
+			return fmt.Errorf("Unknown scope %q", scope)
+		}
+		if scope == scopeAdmin && user.Role != roleAdmin {
+			return fmt.Errorf("Only admins can use the %q scope", scopeAdmin)
+		}
+		if !hasScope(granted, scope) {
+			return fmt.Errorf("Can't grant the %q scope without holding it", scope)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/JustinLi007/rss-aggregator/internal/database"
+)
+
+func TestHasScope(t *testing.T) {
+	tests := []struct {
+		scopes   []string
+		scope    string
+		expected bool
+	}{
+		{nil, scopeNone, true},
+		{[]string{scopePostsRead}, scopePostsRead, true},
+		{[]string{scopePostsRead}, scopePostsWrite, false},
+		{nil, scopeFeedsWrite, false},
+		// admin implies every other scope.
+		{[]string{scopeAdmin}, scopePostsWrite, true},
+		{[]string{scopeAdmin}, scopeAccountWrite, true},
+		{[]string{scopeAdmin}, scopeAdmin, true},
+		{userScopes, scopeAdmin, false},
+	}
+
+	for _, test := range tests {
+		if actual := hasScope(test.scopes, test.scope); actual != test.expected {
+			t.Errorf("hasScope(%v, %q): expected %v, got %v", test.scopes, test.scope, test.expected, actual)
+		}
+	}
+}
+
+// TestAPIKeyScopeCheck runs the scope check middlewareAuth applies to API
+// keys once their user is loaded.
+func TestAPIKeyScopeCheck(t *testing.T) {
+	tests := []struct {
+		role      string
+		keyScopes []string
+		scope     string
+		expected  int
+	}{
+		{roleUser, []string{scopePostsRead}, scopePostsRead, http.StatusOK},
+		{roleUser, []string{scopePostsRead}, scopePostsWrite, http.StatusForbidden},
+		{roleUser, []string{scopeFollowsRead}, scopeFollowsWrite, http.StatusForbidden},
+		{roleUser, nil, scopeNone, http.StatusOK},
+		{roleAdmin, []string{scopeAdmin}, scopeFeedsWrite, http.StatusOK},
+		{roleAdmin, []string{scopeAdmin}, scopeAdmin, http.StatusOK},
+		// A demoted user's admin scope no longer counts.
+		{roleUser, []string{scopeAdmin}, scopeAdmin, http.StatusForbidden},
+		{roleUser, []string{scopeAdmin}, scopePostsRead, http.StatusForbidden},
+		{roleUser, []string{scopeAdmin, scopePostsRead}, scopePostsRead, http.StatusOK},
+	}
+
+	for _, test := range tests {
+		user := database.User{Role: test.role}
+		recorder := httptest.NewRecorder()
+
+		if requireScope(recorder, apiKeyScopes(user, test.keyScopes), test.scope, "API key") {
+			recorder.WriteHeader(http.StatusOK)
+		}
+
+		if recorder.Code != test.expected {
+			t.Errorf("Role %q, key scopes %v, scope %q: expected status %v, got %v", test.role, test.keyScopes, test.scope, test.expected, recorder.Code)
+		}
+	}
+}
+
+func TestAPIKeyScopes(t *testing.T) {
+	keyScopes := []string{scopePostsRead, scopeAdmin}
+
+	scopes := apiKeyScopes(database.User{Role: roleUser}, keyScopes)
+	if len(scopes) != 1 || scopes[0] != scopePostsRead {
+		t.Errorf("Expected scopes %v, got %v", []string{scopePostsRead}, scopes)
+	}
+	if len(keyScopes) != 2 {
+		t.Errorf("Expected key scopes %v unchanged, got %v", []string{scopePostsRead, scopeAdmin}, keyScopes)
+	}
+
+	scopes = apiKeyScopes(database.User{Role: roleAdmin}, keyScopes)
+	if len(scopes) != 2 {
+		t.Errorf("Expected scopes %v, got %v", keyScopes, scopes)
+	}
+}
+
+func TestValidateScopes(t *testing.T) {
+	user := database.User{Role: roleUser}
+	admin := database.User{Role: roleAdmin}
+
+	tests := []struct {
+		user     database.User
+		granted  []string
+		scopes   []string
+		expected bool
+	}{
+		{user, userScopes, []string{scopePostsRead}, true},
+		{user, userScopes, userScopes, true},
+		{user, []string{scopePostsRead}, nil, true},
+		{user, []string{scopePostsRead}, []string{scopePostsRead, scopePostsWrite}, false},
+		{user, []string{scopeFollowsRead}, []string{scopeFollowsWrite}, false},
+		{user, userScopes, []string{"posts:delete"}, false},
+		// Only admins can grant the admin scope, and only from an admin credential.
+		{user, userScopes, []string{scopeAdmin}, false},
+		{user, []string{scopeAdmin}, []string{scopeAdmin}, false},
+		{admin, []string{scopeAdmin}, []string{scopeAdmin}, true},
+		{admin, []string{scopeAdmin}, userScopes, true},
+		{admin, userScopes, []string{scopeAdmin}, false},
+		// Rotating a key broader than the requesting one.
+		{user, []string{scopeAccountWrite}, userScopes, false},
+	}
+
+	for _, test := range tests {
+		err := validateScopes(test.user, test.granted, test.scopes)
+		if actual := err == nil; actual != test.expected {
+			t.Errorf("validateScopes(%v, %v): expected valid %v, got error %v", test.granted, test.scopes, test.expected, err)
+		}
+	}
+}
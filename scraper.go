@@ -26,10 +26,14 @@ type RSSFeed struct {
 }
 
 type RSSItem struct {
-	Title       string `xml:"title"`
-	Link        string `xml:"link"`
-	Description string `xml:"description"`
-	PubDate     string `xml:"pubDate"`
+	Title       string   `xml:"title"`
+	Link        string   `xml:"link"`
+	Description string   `xml:"description"`
+	Content     string   `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+	Author      string   `xml:"author"`
+	Creator     string   `xml:"http://purl.org/dc/elements/1.1/ creator"`
+	Categories  []string `xml:"category"`
+	PubDate     string   `xml:"pubDate"`
 }
 
 func initScraping(db *database.Queries, concurrency int, interval time.Duration) {
@@ -88,6 +92,15 @@ func scrapeFeed(feed Feed, db *database.Queries, wg *sync.WaitGroup, resultChan
 		return
 	}
 
+	if link := strings.TrimSpace(rssFeed.Channel.Link); link != "" && (feed.SiteUrl == nil || *feed.SiteUrl != link) {
+		if err := db.SetFeedSiteURL(context.Background(), database.SetFeedSiteURLParams{
+			ID:      feed.ID,
+			SiteUrl: sql.NullString{String: link, Valid: true},
+		}); err != nil {
+			log.Printf("Failed to save site url of feed %v: %v", feed.Name, err)
+		}
+	}
+
 	saveFeedEntries(db, feed, rssFeed)
 
 	resultChan <- *rssFeed
@@ -118,7 +131,31 @@ func fetchFeed(url string) (*RSSFeed, error) {
 }
 
 func saveFeedEntries(db *database.Queries, feed Feed, rssFeed *RSSFeed) {
+	filterRules := compileFeedFilterRules(context.Background(), db, feed)
+
+	// Retention deleted these posts; the feed still listing them mustn't
+	// bring them back as new.
+	pruned := map[string]bool{}
+	links := make([]string, len(rssFeed.Channel.Item))
+	for i, v := range rssFeed.Channel.Item {
+		links[i] = v.Link
+	}
+	prunedURLs, err := db.GetPrunedPostURLs(context.Background(), database.GetPrunedPostURLsParams{
+		FeedID: feed.ID,
+		Urls:   links,
+	})
+	if err != nil {
+		log.Printf("Failed to load pruned posts of feed %v: %v", feed.Name, err)
+		return
+	}
+	for _, url := range prunedURLs {
+		pruned[url] = true
+	}
+
 	for _, v := range rssFeed.Channel.Item {
+		if pruned[v.Link] {
+			continue
+		}
 		pubDate, err := time.Parse(time.RFC1123Z, v.PubDate)
 		if err != nil {
 			log.Printf("Failed to parse published date: %v", err)
@@ -131,8 +168,22 @@ func saveFeedEntries(db *database.Queries, feed Feed, rssFeed *RSSFeed) {
 		if descStr.String == "" {
 			descStr.Valid = false
 		}
+		contentStr := sql.NullString{
+			String: v.Content,
+			Valid:  v.Content != "",
+		}
+		author := strings.TrimSpace(v.Creator)
+		if author == "" {
+			author = strings.TrimSpace(v.Author)
+		}
+		categories := make([]string, 0, len(v.Categories))
+		for _, category := range v.Categories {
+			if category = strings.TrimSpace(category); category != "" {
+				categories = append(categories, category)
+			}
+		}
 
-		_, err = db.CreatePost(context.Background(), database.CreatePostParams{
+		post, err := db.CreatePost(context.Background(), database.CreatePostParams{
 			ID:          uuid.New(),
 			CreatedAt:   time.Now().UTC(),
 			UpdatedAt:   time.Now().UTC(),
@@ -140,14 +191,36 @@ func saveFeedEntries(db *database.Queries, feed Feed, rssFeed *RSSFeed) {
 			Url:         v.Link,
 			Description: descStr,
 			PublishedAt: pubDate,
-			FeedID:      feed.ID,
+			FeedID:      uuid.NullUUID{UUID: feed.ID, Valid: true},
+			Content:     contentStr,
+			Author:      sql.NullString{String: author, Valid: author != ""},
+			Categories:  categories,
 		})
 		if err != nil {
-			if !strings.Contains(err.Error(), "pq: duplicate key value violates") {
+			if !isDuplicateKeyError(err) {
 				log.Printf("Failed to save post: %v", err)
 			}
 			continue
 		}
+
+		if err := db.MatchSavedSearchesForPost(context.Background(), post.ID); err != nil {
+			log.Printf("Failed to match saved searches: %v", err)
+		}
+
+		rulesPost := databasePostToRulesPost(post, feed.Name)
+		for _, v := range filterRules {
+			if !v.matcher.Match(rulesPost) {
+				continue
+			}
+			if err := applyFilterRule(context.Background(), db, v.rule, post); err != nil {
+				log.Printf("Failed to apply filter rule %v: %v", v.rule.ID, err)
+			}
+		}
+
+		// After the rules, so a notify rule's notification keeps its rule.
+		if err := db.CreateNotificationsForFollowers(context.Background(), post.ID); err != nil {
+			log.Printf("Failed to notify followers: %v", err)
+		}
 	}
 
 	log.Printf("Feed %v collected, %v posts found", feed.Name, len(rssFeed.Channel.Item))
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/JustinLi007/rss-aggregator/internal/auth"
+	"github.com/JustinLi007/rss-aggregator/internal/database"
+	"github.com/google/uuid"
+)
+
+const (
+	sessionCookieName = "session"
+	// csrfCookieName is readable from scripts so a browser client can echo
+	// it back in csrfHeaderName.
+	csrfCookieName = "csrf_token"
+	csrfHeaderName = "X-CSRF-Token"
+
+	sessionDuration = 30 * 24 * time.Hour
+)
+
+type sessionIDContextKey struct{}
+
+func contextWithSessionID(ctx context.Context, id uuid.UUID) context.Context {
+	return context.WithValue(ctx, sessionIDContextKey{}, id)
+}
+
+// sessionIDFromContext returns the session the current request was
+// authenticated with, if it came with a session cookie rather than an API key.
+func sessionIDFromContext(ctx context.Context) (uuid.UUID, bool) {
+	id, ok := ctx.Value(sessionIDContextKey{}).(uuid.UUID)
+	return id, ok
+}
+
+// sessionScopes returns the scopes of a browser session, which has full
+// access to the account.
+func sessionScopes(user database.User) []string {
+	if user.Role == roleAdmin {
+		return append(userScopes[:len(userScopes):len(userScopes)], scopeAdmin)
+	}
+	return userScopes
+}
+
+// isSafeMethod reports whether method can't change state and so doesn't
+// need a CSRF token.
+func isSafeMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
+
+// isSecureRequest reports whether the client reached us over TLS, either
+// directly or through a proxy.
+func isSecureRequest(r *http.Request) bool {
+	return r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https"
+}
+
+// startSession logs user in on this browser and returns the CSRF token of
+// the new session.
+func (cfg *apiConfig) startSession(w http.ResponseWriter, r *http.Request, user database.User) (string, error) {
+	if err := cfg.DB.DeleteExpiredSessions(r.Context(), user.ID); err != nil {
+		log.Printf("Failed to delete expired sessions of user %v: %v", user.ID, err)
+	}
+
+	token, err := auth.GenerateToken()
+	if err != nil {
+		return "", err
+	}
+	csrfToken, err := auth.GenerateToken()
+	if err != nil {
+		return "", err
+	}
+
+	userAgent := r.UserAgent()
+	session, err := cfg.DB.CreateSession(r.Context(), database.CreateSessionParams{
+		ID:         uuid.New(),
+		CreatedAt:  time.Now().UTC(),
+		LastSeenAt: time.Now().UTC(),
+		ExpiresAt:  time.Now().UTC().Add(sessionDuration),
+		TokenHash:  auth.HashToken(token),
+		CsrfToken:  csrfToken,
+		UserAgent:  sql.NullString{String: userAgent, Valid: userAgent != ""},
+		UserID:     user.ID,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	setSessionCookies(w, r, token, csrfToken, session.ExpiresAt)
+	return csrfToken, nil
+}
+
+func setSessionCookies(w http.ResponseWriter, r *http.Request, token, csrfToken string, expiresAt time.Time) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     sessionCookieName,
+		Value:    token,
+		Path:     "/",
+		Expires:  expiresAt,
+		HttpOnly: true,
+		Secure:   isSecureRequest(r),
+		SameSite: http.SameSiteLaxMode,
+	})
+	http.SetCookie(w, &http.Cookie{
+		Name:     csrfCookieName,
+		Value:    csrfToken,
+		Path:     "/",
+		Expires:  expiresAt,
+		Secure:   isSecureRequest(r),
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+func clearSessionCookies(w http.ResponseWriter, r *http.Request) {
+	for _, name := range []string{sessionCookieName, csrfCookieName} {
+		http.SetCookie(w, &http.Cookie{
+			Name:     name,
+			Value:    "",
+			Path:     "/",
+			MaxAge:   -1,
+			HttpOnly: name == sessionCookieName,
+			Secure:   isSecureRequest(r),
+			SameSite: http.SameSiteLaxMode,
+		})
+	}
+}
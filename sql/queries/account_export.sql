@@ -0,0 +1,17 @@
+-- name: GetPostStatesForExport :many
+SELECT posts.id AS post_id, posts.title, posts.url, feeds.url AS feed_url,
+users_posts_states.read_at, users_posts_states.starred_at, users_posts_states.hidden_at
+FROM users_posts_states
+JOIN posts ON posts.id = users_posts_states.post_id
+LEFT JOIN feeds ON feeds.id = posts.feed_id
+WHERE users_posts_states.user_id = $1
+AND (users_posts_states.read_at IS NOT NULL OR users_posts_states.starred_at IS NOT NULL OR users_posts_states.hidden_at IS NOT NULL)
+ORDER BY users_posts_states.created_at ASC, posts.id ASC;
+
+-- name: GetPostLabelsForExport :many
+SELECT posts_labels.label_id, posts.id AS post_id, posts.title, posts.url, posts_labels.created_at
+FROM posts_labels
+JOIN labels ON labels.id = posts_labels.label_id
+JOIN posts ON posts.id = posts_labels.post_id
+WHERE labels.user_id = $1
+ORDER BY posts_labels.created_at ASC, posts.id ASC;
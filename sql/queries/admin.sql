@@ -0,0 +1,30 @@
+-- name: ListUsers :many
+SELECT * FROM users
+WHERE (sqlc.narg(search)::text IS NULL OR users.name ILIKE '%' || sqlc.narg(search) || '%')
+AND (sqlc.narg(role)::text IS NULL OR users.role = sqlc.narg(role))
+ORDER BY users.created_at ASC, users.id ASC
+OFFSET sqlc.arg(row_offset) LIMIT sqlc.arg(row_limit);
+
+-- name: GetUserByID :one
+SELECT * FROM users
+WHERE id = $1;
+
+-- name: DisableUser :one
+UPDATE users
+SET disabled_at = NOW(), updated_at = NOW()
+WHERE id = $1 AND disabled_at IS NULL
+RETURNING *;
+
+-- name: EnableUser :one
+UPDATE users
+SET disabled_at = NULL, updated_at = NOW()
+WHERE id = $1 AND disabled_at IS NOT NULL
+RETURNING *;
+
+-- name: DeleteUser :execrows
+DELETE FROM users
+WHERE id = $1;
+
+-- name: CountAdmins :one
+SELECT COUNT(*) FROM users
+WHERE role = 'admin' AND disabled_at IS NULL;
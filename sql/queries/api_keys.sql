@@ -0,0 +1,38 @@
+-- name: CreateAPIKey :one
+INSERT INTO api_keys(id, created_at, updated_at, name, key_hash, prefix, expires_at, scopes, user_id)
+VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9)
+RETURNING *;
+
+-- name: GetAPIKeysByUser :many
+SELECT * FROM api_keys
+WHERE user_id = $1 AND revoked_at IS NULL
+ORDER BY created_at ASC;
+
+-- name: GetAPIKeyByID :one
+SELECT * FROM api_keys
+WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL;
+
+-- name: RevokeAPIKey :execrows
+UPDATE api_keys
+SET revoked_at = NOW(), updated_at = NOW()
+WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL;
+
+-- name: GetUserByAPIKey :one
+SELECT sqlc.embed(users), api_keys.id AS api_key_id, api_keys.scopes AS api_key_scopes
+FROM users
+JOIN api_keys ON api_keys.user_id = users.id
+WHERE api_keys.prefix = $1 AND api_keys.key_hash = $2
+AND api_keys.revoked_at IS NULL
+AND (api_keys.expires_at IS NULL OR api_keys.expires_at > NOW())
+AND users.disabled_at IS NULL;
+
+-- name: TouchAPIKey :exec
+UPDATE api_keys
+SET last_used_at = NOW()
+WHERE id = $1
+AND (last_used_at IS NULL OR last_used_at < NOW() - INTERVAL '1 minute');
+
+-- name: RevokeAllAPIKeys :execrows
+UPDATE api_keys
+SET revoked_at = NOW(), updated_at = NOW()
+WHERE user_id = $1 AND revoked_at IS NULL;
@@ -1,13 +1,21 @@
 -- name: CreateFeed :one
-INSERT INTO feeds(id, created_at, updated_at, name, url, user_id)
-VALUES($1, $2, $3, $4, $5, $6)
+INSERT INTO feeds(id, created_at, updated_at, name, url, user_id, canonical_url)
+VALUES($1, $2, $3, $4, $5, $6, $7)
 RETURNING *;
 
 -- name: GetFeeds :many
 SELECT * FROM feeds;
 
+-- name: GetFeedByCanonicalURL :one
+SELECT * FROM feeds
+WHERE canonical_url = $1;
+
 -- name: GetNextFeedsToFetch :many
 SELECT * FROM feeds
+WHERE EXISTS (
+    SELECT 1 FROM users_feeds_follows
+    WHERE users_feeds_follows.feed_id = feeds.id
+)
 ORDER BY last_fetched_at ASC NULLS FIRST
 LIMIT $1;
 
@@ -16,3 +24,76 @@ UPDATE feeds
 SET last_fetched_at = NOW(), updated_at = NOW()
 WHERE id = $1
 RETURNING *;
+
+-- name: SetFeedSiteURL :exec
+UPDATE feeds
+SET site_url = $2
+WHERE id = $1;
+
+-- name: GetFeedByID :one
+SELECT * FROM feeds
+WHERE id = $1;
+
+-- name: UpdateFeed :one
+UPDATE feeds
+SET name = COALESCE(sqlc.narg(name), name),
+url = COALESCE(sqlc.narg(url), url),
+canonical_url = COALESCE(sqlc.narg(canonical_url), canonical_url),
+last_fetched_at = CASE WHEN sqlc.narg(url)::text IS NULL THEN last_fetched_at ELSE NULL END,
+updated_at = NOW()
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: TransferFeedOwnership :one
+UPDATE feeds
+SET user_id = next_owner.user_id, updated_at = NOW()
+FROM (
+    SELECT users_feeds_follows.user_id FROM users_feeds_follows
+    WHERE users_feeds_follows.feed_id = sqlc.arg(id)
+    AND users_feeds_follows.user_id <> sqlc.arg(user_id)
+    ORDER BY users_feeds_follows.created_at ASC
+    LIMIT 1
+) AS next_owner
+WHERE feeds.id = sqlc.arg(id)
+RETURNING feeds.*;
+
+-- name: DeleteFeed :execrows
+DELETE FROM feeds
+WHERE id = $1;
+
+-- name: MarkOrphanedFeedsDormant :execrows
+UPDATE feeds
+SET dormant_since = NOW(), updated_at = NOW()
+WHERE dormant_since IS NULL
+AND NOT EXISTS (
+    SELECT 1 FROM users_feeds_follows
+    WHERE users_feeds_follows.feed_id = feeds.id
+);
+
+-- name: ResumeFollowedFeeds :execrows
+UPDATE feeds
+SET dormant_since = NULL, updated_at = NOW()
+WHERE dormant_since IS NOT NULL
+AND EXISTS (
+    SELECT 1 FROM users_feeds_follows
+    WHERE users_feeds_follows.feed_id = feeds.id
+);
+
+-- name: ResumeFeed :exec
+UPDATE feeds
+SET dormant_since = NULL, updated_at = NOW()
+WHERE id = $1 AND dormant_since IS NOT NULL;
+
+-- name: PurgeDormantFeeds :execrows
+DELETE FROM feeds
+WHERE dormant_since < sqlc.arg(dormant_before)::timestamp
+AND NOT EXISTS (
+    SELECT 1 FROM users_feeds_follows
+    WHERE users_feeds_follows.feed_id = feeds.id
+);
+
+-- name: SetFeedRetention :one
+UPDATE feeds
+SET retention_max_age_days = $2, retention_max_posts = $3, updated_at = NOW()
+WHERE id = $1
+RETURNING *;
@@ -0,0 +1,29 @@
+-- name: CreateFilterRule :one
+INSERT INTO filter_rules(id, created_at, updated_at, name, conditions, match_all, actions, tag, enabled, user_id)
+VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+RETURNING *;
+
+-- name: GetFilterRulesByUser :many
+SELECT * FROM filter_rules
+WHERE user_id = $1
+ORDER BY created_at ASC;
+
+-- name: GetFilterRuleByID :one
+SELECT * FROM filter_rules
+WHERE id = $1 AND user_id = $2;
+
+-- name: UpdateFilterRule :one
+UPDATE filter_rules
+SET name = $1, conditions = $2, match_all = $3, actions = $4, tag = $5, enabled = $6, updated_at = NOW()
+WHERE id = $7 AND user_id = $8
+RETURNING *;
+
+-- name: DeleteFilterRule :execrows
+DELETE FROM filter_rules
+WHERE id = $1 AND user_id = $2;
+
+-- name: GetEnabledFilterRulesForFeed :many
+SELECT filter_rules.* FROM filter_rules
+JOIN users_feeds_follows ON users_feeds_follows.user_id = filter_rules.user_id
+WHERE users_feeds_follows.feed_id = $1 AND filter_rules.enabled
+ORDER BY filter_rules.created_at ASC;
@@ -0,0 +1,46 @@
+-- name: CreateFolder :one
+INSERT INTO folders(id, created_at, updated_at, name, user_id, position)
+VALUES($1, $2, $3, $4, $5, (SELECT COALESCE(MAX(position) + 1, 0) FROM folders WHERE user_id = $5))
+RETURNING *;
+
+-- name: GetFoldersByUser :many
+SELECT * FROM folders
+WHERE user_id = $1
+ORDER BY position ASC, name ASC;
+
+-- name: GetFolderByID :one
+SELECT * FROM folders
+WHERE id = $1 AND user_id = $2;
+
+-- name: GetFolderByName :one
+SELECT * FROM folders
+WHERE user_id = $1 AND name = $2;
+
+-- name: UpdateFolder :one
+UPDATE folders
+SET name = COALESCE(sqlc.narg(name), name),
+position = COALESCE(sqlc.narg(position), position),
+updated_at = NOW()
+WHERE id = sqlc.arg(id) AND user_id = sqlc.arg(user_id)
+RETURNING *;
+
+-- name: ReorderFolders :execrows
+UPDATE folders
+SET position = array_position(sqlc.arg(folder_ids)::uuid[], folders.id) - 1, updated_at = NOW()
+WHERE folders.id = ANY(sqlc.arg(folder_ids)::uuid[]) AND folders.user_id = sqlc.arg(user_id);
+
+-- name: DeleteFolder :execrows
+DELETE FROM folders
+WHERE id = $1 AND user_id = $2;
+
+-- name: GetUnreadCountsByFolder :many
+SELECT users_feeds_follows.folder_id::uuid AS folder_id, COUNT(posts.id) AS unread_count
+FROM users_feeds_follows
+JOIN posts ON posts.feed_id = users_feeds_follows.feed_id
+LEFT JOIN users_posts_states ON users_posts_states.post_id = posts.id AND users_posts_states.user_id = users_feeds_follows.user_id
+WHERE users_feeds_follows.user_id = $1
+AND users_feeds_follows.folder_id IS NOT NULL
+AND NOT users_feeds_follows.muted
+AND users_posts_states.read_at IS NULL
+AND users_posts_states.hidden_at IS NULL
+GROUP BY users_feeds_follows.folder_id;
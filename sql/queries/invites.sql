@@ -0,0 +1,21 @@
+-- name: CreateInvite :one
+INSERT INTO invites(id, created_at, updated_at, code, max_uses, expires_at, created_by)
+VALUES($1, $2, $3, $4, $5, $6, $7)
+RETURNING *;
+
+-- name: GetInvites :many
+SELECT * FROM invites
+ORDER BY created_at DESC;
+
+-- name: RevokeInvite :execrows
+UPDATE invites
+SET revoked_at = NOW(), updated_at = NOW()
+WHERE id = $1 AND revoked_at IS NULL;
+
+-- name: UseInvite :one
+UPDATE invites
+SET uses = uses + 1, updated_at = NOW()
+WHERE code = $1 AND revoked_at IS NULL
+AND (expires_at IS NULL OR expires_at > NOW())
+AND uses < max_uses
+RETURNING *;
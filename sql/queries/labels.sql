@@ -0,0 +1,45 @@
+-- name: CreateLabel :one
+INSERT INTO labels(id, created_at, updated_at, name, user_id)
+VALUES($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: UpsertLabel :one
+INSERT INTO labels(id, created_at, updated_at, name, user_id)
+VALUES($1, $2, $3, $4, $5)
+ON CONFLICT (user_id, name) DO UPDATE
+SET name = EXCLUDED.name
+RETURNING *;
+
+-- name: GetLabelsByUser :many
+SELECT sqlc.embed(labels),
+COUNT(posts_labels.post_id) AS post_count,
+COUNT(posts_labels.post_id) FILTER (WHERE users_posts_states.read_at IS NULL) AS unread_count
+FROM labels
+LEFT JOIN posts_labels ON posts_labels.label_id = labels.id
+LEFT JOIN users_posts_states ON users_posts_states.post_id = posts_labels.post_id AND users_posts_states.user_id = labels.user_id
+WHERE labels.user_id = $1
+GROUP BY labels.id
+ORDER BY labels.name ASC;
+
+-- name: GetLabelByID :one
+SELECT * FROM labels
+WHERE id = $1 AND user_id = $2;
+
+-- name: UpdateLabel :one
+UPDATE labels
+SET name = $1, updated_at = NOW()
+WHERE id = $2 AND user_id = $3
+RETURNING *;
+
+-- name: DeleteLabel :execrows
+DELETE FROM labels
+WHERE id = $1 AND user_id = $2;
+
+-- name: AddPostLabel :execrows
+INSERT INTO posts_labels(label_id, post_id, created_at)
+VALUES($1, $2, $3)
+ON CONFLICT DO NOTHING;
+
+-- name: RemovePostLabel :execrows
+DELETE FROM posts_labels
+WHERE label_id = $1 AND post_id = $2;
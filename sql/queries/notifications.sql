@@ -0,0 +1,26 @@
+-- name: CreateNotification :execrows
+INSERT INTO notifications(id, created_at, user_id, post_id, filter_rule_id)
+VALUES($1, $2, $3, $4, $5)
+ON CONFLICT (user_id, post_id) DO NOTHING;
+
+-- name: GetNotificationsByUser :many
+SELECT sqlc.embed(notifications), sqlc.embed(posts)
+FROM notifications
+JOIN posts ON posts.id = notifications.post_id
+WHERE notifications.user_id = $1
+ORDER BY notifications.created_at DESC, notifications.id DESC
+LIMIT $2;
+
+-- name: DeleteNotification :execrows
+DELETE FROM notifications
+WHERE id = $1 AND user_id = $2;
+
+-- name: CreateNotificationsForFollowers :exec
+INSERT INTO notifications(id, created_at, user_id, post_id)
+SELECT gen_random_uuid(), NOW(), users_feeds_follows.user_id, posts.id
+FROM posts
+JOIN users_feeds_follows ON users_feeds_follows.feed_id = posts.feed_id
+WHERE posts.id = $1
+AND users_feeds_follows.notifications = 'all'
+AND NOT users_feeds_follows.muted
+ON CONFLICT (user_id, post_id) DO NOTHING;
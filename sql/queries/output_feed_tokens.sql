@@ -0,0 +1,20 @@
+-- name: CreateOutputFeedToken :one
+INSERT INTO output_feed_tokens(id, created_at, updated_at, name, token, user_id)
+VALUES($1, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: GetOutputFeedTokensByUser :many
+SELECT * FROM output_feed_tokens
+WHERE user_id = $1 AND revoked_at IS NULL
+ORDER BY created_at ASC;
+
+-- name: RevokeOutputFeedToken :execrows
+UPDATE output_feed_tokens
+SET revoked_at = NOW(), updated_at = NOW()
+WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL;
+
+-- name: GetUserByOutputFeedToken :one
+SELECT users.* FROM users
+JOIN output_feed_tokens ON output_feed_tokens.user_id = users.id
+WHERE output_feed_tokens.token = $1 AND output_feed_tokens.revoked_at IS NULL
+AND users.disabled_at IS NULL;
@@ -1,11 +1,105 @@
 -- name: CreatePost :one
-INSERT INTO posts(id, created_at, updated_at, title, url, description, published_at, feed_id)
-VALUES($1, $2, $3, $4, $5, $6, $7, $8)
+INSERT INTO posts(id, created_at, updated_at, title, url, description, published_at, feed_id, content, author, categories)
+VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
 RETURNING *;
 
--- name: GetPostByUser :many
+-- name: GetPostsForUser :many
 SELECT posts.* FROM posts
-JOIN feeds ON posts.feed_id = feeds.id
-WHERE feeds.user_id = $1
-ORDER BY posts.published_at DESC
+JOIN users_feeds_follows ON users_feeds_follows.feed_id = posts.feed_id
+WHERE users_feeds_follows.user_id = sqlc.arg(user_id)
+AND (sqlc.narg(feed_ids)::uuid[] IS NULL OR posts.feed_id = ANY(sqlc.narg(feed_ids)::uuid[]))
+AND (sqlc.narg(folder_id)::uuid IS NULL OR users_feeds_follows.folder_id = sqlc.narg(folder_id))
+AND (sqlc.narg(label_id)::uuid IS NULL OR EXISTS (
+    SELECT 1 FROM posts_labels
+    WHERE posts_labels.label_id = sqlc.narg(label_id)
+    AND posts_labels.post_id = posts.id
+))
+AND (NOT users_feeds_follows.hide_from_timeline OR sqlc.narg(feed_ids) IS NOT NULL OR sqlc.narg(folder_id) IS NOT NULL OR sqlc.narg(label_id) IS NOT NULL)
+AND (sqlc.narg(since)::timestamp IS NULL OR posts.published_at >= sqlc.narg(since))
+AND (sqlc.narg(until)::timestamp IS NULL OR posts.published_at < sqlc.narg(until))
+AND (sqlc.narg(before_published_at)::timestamp IS NULL OR (posts.published_at, posts.id) < (sqlc.narg(before_published_at), sqlc.narg(before_id)::uuid))
+AND (sqlc.narg(after_published_at)::timestamp IS NULL OR (posts.published_at, posts.id) > (sqlc.narg(after_published_at), sqlc.narg(after_id)::uuid))
+AND (NOT sqlc.arg(unread_only)::boolean OR NOT EXISTS (
+    SELECT 1 FROM users_posts_states
+    WHERE users_posts_states.user_id = users_feeds_follows.user_id
+    AND users_posts_states.post_id = posts.id
+    AND users_posts_states.read_at IS NOT NULL
+))
+AND NOT EXISTS (
+    SELECT 1 FROM users_posts_states
+    WHERE users_posts_states.user_id = users_feeds_follows.user_id
+    AND users_posts_states.post_id = posts.id
+    AND users_posts_states.hidden_at IS NOT NULL
+)
+ORDER BY posts.published_at DESC, posts.id DESC
+LIMIT sqlc.arg(row_limit);
+
+-- name: GetPostsForUserOldestFirst :many
+SELECT posts.* FROM posts
+JOIN users_feeds_follows ON users_feeds_follows.feed_id = posts.feed_id
+WHERE users_feeds_follows.user_id = sqlc.arg(user_id)
+AND (sqlc.narg(feed_ids)::uuid[] IS NULL OR posts.feed_id = ANY(sqlc.narg(feed_ids)::uuid[]))
+AND (sqlc.narg(folder_id)::uuid IS NULL OR users_feeds_follows.folder_id = sqlc.narg(folder_id))
+AND (sqlc.narg(label_id)::uuid IS NULL OR EXISTS (
+    SELECT 1 FROM posts_labels
+    WHERE posts_labels.label_id = sqlc.narg(label_id)
+    AND posts_labels.post_id = posts.id
+))
+AND (NOT users_feeds_follows.hide_from_timeline OR sqlc.narg(feed_ids) IS NOT NULL OR sqlc.narg(folder_id) IS NOT NULL OR sqlc.narg(label_id) IS NOT NULL)
+AND (sqlc.narg(since)::timestamp IS NULL OR posts.published_at >= sqlc.narg(since))
+AND (sqlc.narg(until)::timestamp IS NULL OR posts.published_at < sqlc.narg(until))
+AND (sqlc.narg(before_published_at)::timestamp IS NULL OR (posts.published_at, posts.id) < (sqlc.narg(before_published_at), sqlc.narg(before_id)::uuid))
+AND (sqlc.narg(after_published_at)::timestamp IS NULL OR (posts.published_at, posts.id) > (sqlc.narg(after_published_at), sqlc.narg(after_id)::uuid))
+AND (NOT sqlc.arg(unread_only)::boolean OR NOT EXISTS (
+    SELECT 1 FROM users_posts_states
+    WHERE users_posts_states.user_id = users_feeds_follows.user_id
+    AND users_posts_states.post_id = posts.id
+    AND users_posts_states.read_at IS NOT NULL
+))
+AND NOT EXISTS (
+    SELECT 1 FROM users_posts_states
+    WHERE users_posts_states.user_id = users_feeds_follows.user_id
+    AND users_posts_states.post_id = posts.id
+    AND users_posts_states.hidden_at IS NOT NULL
+)
+ORDER BY posts.published_at ASC, posts.id ASC
+LIMIT sqlc.arg(row_limit);
+
+-- name: GetStarredPostsForUser :many
+SELECT posts.* FROM posts
+JOIN users_posts_states ON users_posts_states.post_id = posts.id
+WHERE users_posts_states.user_id = sqlc.arg(user_id)
+AND users_posts_states.starred_at IS NOT NULL
+AND (sqlc.narg(before_published_at)::timestamp IS NULL OR (posts.published_at, posts.id) < (sqlc.narg(before_published_at), sqlc.narg(before_id)::uuid))
+ORDER BY posts.published_at DESC, posts.id DESC
+LIMIT sqlc.arg(row_limit);
+
+-- name: SearchPostsForUser :many
+SELECT sqlc.embed(posts),
+ts_rank_cd(to_tsvector('english', posts.title || ' ' || coalesce(posts.description, '') || ' ' || coalesce(posts.content, '')), tsq)::real AS rank,
+ts_headline('english', replace(replace(replace(replace(replace(regexp_replace(posts.title || ' ' || coalesce(posts.description, '') || ' ' || coalesce(posts.content, ''), '<[^>]*>', ' ', 'g'), '&', '&amp;'), '<', '&lt;'), '>', '&gt;'), '"', '&quot;'), '''', '&#39;'), tsq, 'StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MinWords=10, MaxWords=30')::text AS snippet
+FROM posts
+JOIN users_feeds_follows ON users_feeds_follows.feed_id = posts.feed_id
+LEFT JOIN users_posts_states ON users_posts_states.post_id = posts.id AND users_posts_states.user_id = users_feeds_follows.user_id
+CROSS JOIN to_tsquery('english', sqlc.arg(search_query)) AS tsq
+WHERE users_feeds_follows.user_id = sqlc.arg(user_id)
+AND to_tsvector('english', posts.title || ' ' || coalesce(posts.description, '') || ' ' || coalesce(posts.content, '')) @@ tsq
+AND (sqlc.narg(feed_ids)::uuid[] IS NULL OR posts.feed_id = ANY(sqlc.narg(feed_ids)::uuid[]))
+AND (sqlc.narg(folder_id)::uuid IS NULL OR users_feeds_follows.folder_id = sqlc.narg(folder_id))
+AND (NOT users_feeds_follows.hide_from_timeline OR sqlc.narg(feed_ids) IS NOT NULL OR sqlc.narg(folder_id) IS NOT NULL)
+AND users_posts_states.hidden_at IS NULL
+AND (sqlc.narg(since)::timestamp IS NULL OR posts.published_at >= sqlc.narg(since))
+AND (sqlc.narg(until)::timestamp IS NULL OR posts.published_at < sqlc.narg(until))
+AND (sqlc.narg(is_read)::boolean IS NULL OR (users_posts_states.read_at IS NOT NULL) = sqlc.narg(is_read))
+AND (sqlc.narg(is_starred)::boolean IS NULL OR (users_posts_states.starred_at IS NOT NULL) = sqlc.narg(is_starred))
+ORDER BY rank DESC, posts.published_at DESC, posts.id DESC
+OFFSET sqlc.arg(row_offset) LIMIT sqlc.arg(row_limit);
+
+-- name: GetRecentPostsForUser :many
+SELECT sqlc.embed(posts), feeds.name AS feed_name
+FROM posts
+JOIN feeds ON feeds.id = posts.feed_id
+JOIN users_feeds_follows ON users_feeds_follows.feed_id = posts.feed_id
+WHERE users_feeds_follows.user_id = $1
+ORDER BY posts.published_at DESC, posts.id DESC
 LIMIT $2;
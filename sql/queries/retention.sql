@@ -0,0 +1,103 @@
+-- Posts nobody starred or labelled and, within the unread window, that every
+-- follower has read are the only ones retention deletes.
+-- The URLs of deleted posts are kept in pruned_posts so the scraper doesn't
+-- insert them again while their feed still lists them, and forgotten once
+-- the feed can be expected to have dropped them.
+
+-- name: PruneExpiredPosts :execrows
+WITH pruned AS (
+    DELETE FROM posts
+    WHERE posts.id IN (
+        SELECT candidates.id FROM posts AS candidates
+        JOIN feeds ON feeds.id = candidates.feed_id
+        WHERE COALESCE(feeds.retention_max_age_days, sqlc.narg(default_max_age_days)::integer) IS NOT NULL
+        AND candidates.published_at < NOW() - make_interval(days => COALESCE(feeds.retention_max_age_days, sqlc.narg(default_max_age_days)::integer))
+        AND NOT EXISTS (
+            SELECT 1 FROM users_posts_states
+            WHERE users_posts_states.post_id = candidates.id
+            AND users_posts_states.starred_at IS NOT NULL
+        )
+        AND NOT EXISTS (
+            SELECT 1 FROM posts_labels
+            WHERE posts_labels.post_id = candidates.id
+        )
+        AND NOT (candidates.published_at > NOW() - make_interval(days => sqlc.arg(unread_window_days)::integer) AND EXISTS (
+            SELECT 1 FROM users_feeds_follows
+            LEFT JOIN users_posts_states ON users_posts_states.user_id = users_feeds_follows.user_id AND users_posts_states.post_id = candidates.id
+            WHERE users_feeds_follows.feed_id = candidates.feed_id
+            AND users_posts_states.read_at IS NULL
+        ))
+        LIMIT sqlc.arg(batch_size)
+    )
+    RETURNING posts.feed_id, posts.url
+)
+INSERT INTO pruned_posts(feed_id, url, pruned_at)
+SELECT pruned.feed_id, pruned.url, NOW() FROM pruned
+ON CONFLICT (feed_id, url) DO UPDATE
+SET pruned_at = EXCLUDED.pruned_at;
+
+-- name: PruneExcessPosts :execrows
+WITH pruned AS (
+    DELETE FROM posts
+    WHERE posts.id IN (
+        SELECT ranked.id FROM (
+            SELECT candidates.id, candidates.feed_id, candidates.published_at,
+            ROW_NUMBER() OVER (PARTITION BY candidates.feed_id ORDER BY candidates.published_at DESC, candidates.id DESC) AS position,
+            COALESCE(feeds.retention_max_posts, sqlc.narg(default_max_posts)::integer) AS max_posts
+            FROM posts AS candidates
+            JOIN feeds ON feeds.id = candidates.feed_id
+            WHERE COALESCE(feeds.retention_max_posts, sqlc.narg(default_max_posts)::integer) IS NOT NULL
+        ) AS ranked
+        WHERE ranked.position > ranked.max_posts
+        AND NOT EXISTS (
+            SELECT 1 FROM users_posts_states
+            WHERE users_posts_states.post_id = ranked.id
+            AND users_posts_states.starred_at IS NOT NULL
+        )
+        AND NOT EXISTS (
+            SELECT 1 FROM posts_labels
+            WHERE posts_labels.post_id = ranked.id
+        )
+        AND NOT (ranked.published_at > NOW() - make_interval(days => sqlc.arg(unread_window_days)::integer) AND EXISTS (
+            SELECT 1 FROM users_feeds_follows
+            LEFT JOIN users_posts_states ON users_posts_states.user_id = users_feeds_follows.user_id AND users_posts_states.post_id = ranked.id
+            WHERE users_feeds_follows.feed_id = ranked.feed_id
+            AND users_posts_states.read_at IS NULL
+        ))
+        LIMIT sqlc.arg(batch_size)
+    )
+    RETURNING posts.feed_id, posts.url
+)
+INSERT INTO pruned_posts(feed_id, url, pruned_at)
+SELECT pruned.feed_id, pruned.url, NOW() FROM pruned
+ON CONFLICT (feed_id, url) DO UPDATE
+SET pruned_at = EXCLUDED.pruned_at;
+
+-- name: PruneDetachedPosts :execrows
+DELETE FROM posts
+WHERE posts.id IN (
+    SELECT candidates.id FROM posts AS candidates
+    WHERE candidates.feed_id IS NULL
+    AND NOT EXISTS (
+        SELECT 1 FROM users_posts_states
+        WHERE users_posts_states.post_id = candidates.id
+        AND users_posts_states.starred_at IS NOT NULL
+    )
+    AND NOT EXISTS (
+        SELECT 1 FROM posts_labels
+        WHERE posts_labels.post_id = candidates.id
+    )
+    LIMIT sqlc.arg(batch_size)
+);
+
+-- name: GetPrunedPostURLs :many
+SELECT url FROM pruned_posts
+WHERE feed_id = sqlc.arg(feed_id) AND url = ANY(sqlc.arg(urls)::text[]);
+
+-- name: ForgetPrunedPosts :execrows
+DELETE FROM pruned_posts
+WHERE (feed_id, url) IN (
+    SELECT candidates.feed_id, candidates.url FROM pruned_posts AS candidates
+    WHERE candidates.pruned_at < NOW() - make_interval(days => sqlc.arg(horizon_days)::integer)
+    LIMIT sqlc.arg(batch_size)
+);
@@ -0,0 +1,70 @@
+-- name: CreateSavedSearch :one
+INSERT INTO saved_searches(id, created_at, updated_at, name, query, ts_query, user_id)
+VALUES($1, $2, $3, $4, $5, $6, $7)
+RETURNING *;
+
+-- name: GetSavedSearchesByUser :many
+SELECT * FROM saved_searches
+WHERE user_id = $1
+ORDER BY name ASC;
+
+-- name: GetSavedSearchByID :one
+SELECT * FROM saved_searches
+WHERE id = $1 AND user_id = $2;
+
+-- name: UpdateSavedSearch :one
+UPDATE saved_searches
+SET name = COALESCE(sqlc.narg(name), name),
+query = COALESCE(sqlc.narg(query), query),
+ts_query = COALESCE(sqlc.narg(ts_query), ts_query),
+updated_at = NOW()
+WHERE id = sqlc.arg(id) AND user_id = sqlc.arg(user_id)
+RETURNING *;
+
+-- name: DeleteSavedSearch :execrows
+DELETE FROM saved_searches
+WHERE id = $1 AND user_id = $2;
+
+-- name: DeleteSavedSearchMatches :exec
+DELETE FROM saved_search_matches
+WHERE saved_search_id = $1;
+
+-- name: MatchPostsForSavedSearch :execrows
+INSERT INTO saved_search_matches(saved_search_id, post_id, created_at)
+SELECT saved_searches.id, posts.id, NOW()
+FROM saved_searches
+JOIN users_feeds_follows ON users_feeds_follows.user_id = saved_searches.user_id
+JOIN posts ON posts.feed_id = users_feeds_follows.feed_id
+WHERE saved_searches.id = $1
+AND to_tsvector('english', posts.title || ' ' || coalesce(posts.description, '') || ' ' || coalesce(posts.content, '')) @@ to_tsquery('english', saved_searches.ts_query)
+ON CONFLICT DO NOTHING;
+
+-- name: MatchSavedSearchesForPost :exec
+INSERT INTO saved_search_matches(saved_search_id, post_id, created_at)
+SELECT saved_searches.id, posts.id, NOW()
+FROM posts
+JOIN users_feeds_follows ON users_feeds_follows.feed_id = posts.feed_id
+JOIN saved_searches ON saved_searches.user_id = users_feeds_follows.user_id
+WHERE posts.id = $1
+AND to_tsvector('english', posts.title || ' ' || coalesce(posts.description, '') || ' ' || coalesce(posts.content, '')) @@ to_tsquery('english', saved_searches.ts_query)
+ON CONFLICT DO NOTHING;
+
+-- name: GetUnreadCountsBySavedSearch :many
+SELECT saved_search_matches.saved_search_id, COUNT(saved_search_matches.post_id) AS unread_count
+FROM saved_search_matches
+JOIN saved_searches ON saved_searches.id = saved_search_matches.saved_search_id
+JOIN posts ON posts.id = saved_search_matches.post_id
+JOIN users_feeds_follows ON users_feeds_follows.feed_id = posts.feed_id AND users_feeds_follows.user_id = saved_searches.user_id
+LEFT JOIN users_posts_states ON users_posts_states.post_id = saved_search_matches.post_id AND users_posts_states.user_id = saved_searches.user_id
+WHERE saved_searches.user_id = $1 AND users_posts_states.read_at IS NULL
+GROUP BY saved_search_matches.saved_search_id;
+
+-- name: GetPostsForSavedSearch :many
+SELECT posts.* FROM posts
+JOIN saved_search_matches ON saved_search_matches.post_id = posts.id
+JOIN saved_searches ON saved_searches.id = saved_search_matches.saved_search_id
+JOIN users_feeds_follows ON users_feeds_follows.feed_id = posts.feed_id AND users_feeds_follows.user_id = saved_searches.user_id
+WHERE saved_searches.id = sqlc.arg(saved_search_id) AND saved_searches.user_id = sqlc.arg(user_id)
+AND (sqlc.narg(before_published_at)::timestamp IS NULL OR (posts.published_at, posts.id) < (sqlc.narg(before_published_at), sqlc.narg(before_id)::uuid))
+ORDER BY posts.published_at DESC, posts.id DESC
+LIMIT sqlc.arg(row_limit);
@@ -0,0 +1,35 @@
+-- name: CreateSession :one
+INSERT INTO sessions(id, created_at, last_seen_at, expires_at, token_hash, csrf_token, user_agent, user_id)
+VALUES($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING *;
+
+-- name: GetUserBySession :one
+SELECT sqlc.embed(users), sessions.id AS session_id, sessions.csrf_token AS session_csrf_token
+FROM users
+JOIN sessions ON sessions.user_id = users.id
+WHERE sessions.token_hash = $1 AND sessions.expires_at > NOW()
+AND users.disabled_at IS NULL;
+
+-- name: TouchSession :exec
+UPDATE sessions
+SET last_seen_at = NOW()
+WHERE id = $1
+AND last_seen_at < NOW() - INTERVAL '1 minute';
+
+-- name: GetSessionsByUser :many
+SELECT * FROM sessions
+WHERE user_id = $1 AND expires_at > NOW()
+ORDER BY last_seen_at DESC;
+
+-- name: DeleteSession :execrows
+DELETE FROM sessions
+WHERE id = $1 AND user_id = $2;
+
+-- name: DeleteOtherSessions :exec
+DELETE FROM sessions
+WHERE user_id = sqlc.arg(user_id)
+AND (sqlc.narg(keep_id)::uuid IS NULL OR id <> sqlc.narg(keep_id));
+
+-- name: DeleteExpiredSessions :exec
+DELETE FROM sessions
+WHERE user_id = $1 AND expires_at <= NOW();
@@ -0,0 +1,8 @@
+-- name: GetUserByIdentity :one
+SELECT users.* FROM users
+JOIN user_identities ON user_identities.user_id = users.id
+WHERE user_identities.issuer = $1 AND user_identities.subject = $2;
+
+-- name: CreateUserIdentity :exec
+INSERT INTO user_identities(id, created_at, issuer, subject, user_id)
+VALUES($1, $2, $3, $4, $5);
@@ -1,8 +1,24 @@
 -- name: CreateUser :one
-INSERT INTO users(id, created_at, updated_at, name, api_key)
-VALUES($1, $2, $3, $4, DEFAULT)
+INSERT INTO users(id, created_at, updated_at, name, password_hash)
+VALUES($1, $2, $3, $4, $5)
 RETURNING *;
 
--- name: GetUserByAPIKey :one
+-- name: GetUserByName :one
 SELECT * FROM users
-WHERE api_key = $1;
+WHERE lower(name) = lower(sqlc.arg(name)) AND password_hash IS NOT NULL;
+
+-- name: SetUserPassword :exec
+UPDATE users
+SET password_hash = $2, updated_at = NOW()
+WHERE id = $1;
+
+-- name: SetUserRole :exec
+UPDATE users
+SET role = $2, updated_at = NOW()
+WHERE id = $1 AND role <> $2;
+
+-- name: UserNameExists :one
+SELECT EXISTS (
+    SELECT 1 FROM users
+    WHERE lower(name) = lower(sqlc.arg(name))
+);
@@ -8,9 +8,45 @@ DELETE FROM users_feeds_follows
 WHERE id = $1 AND user_id = $2;
 
 -- name: GetFeedFollows :many
-SELECT * FROM users_feeds_follows
-WHERE user_id = $1;
+SELECT sqlc.embed(users_feeds_follows), feeds.name AS feed_name
+FROM users_feeds_follows
+JOIN feeds ON feeds.id = users_feeds_follows.feed_id
+WHERE users_feeds_follows.user_id = $1
+ORDER BY users_feeds_follows.sort_priority DESC, users_feeds_follows.created_at ASC;
 
 -- name: GetFeedFollowByID :one
 SELECT * FROM users_feeds_follows
 WHERE id = $1;
+
+-- name: GetFeedFollowByUserAndFeed :one
+SELECT * FROM users_feeds_follows
+WHERE user_id = $1 AND feed_id = $2;
+
+-- name: SetFeedFollowFolder :one
+UPDATE users_feeds_follows
+SET folder_id = sqlc.narg(folder_id), updated_at = NOW()
+WHERE users_feeds_follows.id = sqlc.arg(id) AND users_feeds_follows.user_id = sqlc.arg(user_id)
+AND (sqlc.narg(folder_id)::uuid IS NULL OR EXISTS (
+    SELECT 1 FROM folders
+    WHERE folders.id = sqlc.narg(folder_id) AND folders.user_id = sqlc.arg(user_id)
+))
+RETURNING *;
+
+-- name: UpdateFeedFollowSettings :one
+UPDATE users_feeds_follows
+SET title = CASE WHEN sqlc.narg(title)::text IS NULL THEN title ELSE NULLIF(sqlc.narg(title), '') END,
+muted = COALESCE(sqlc.narg(muted), muted),
+notifications = COALESCE(sqlc.narg(notifications), notifications),
+sort_priority = COALESCE(sqlc.narg(sort_priority), sort_priority),
+hide_from_timeline = COALESCE(sqlc.narg(hide_from_timeline), hide_from_timeline),
+updated_at = NOW()
+WHERE id = sqlc.arg(id) AND user_id = sqlc.arg(user_id)
+RETURNING *;
+
+-- name: GetFeedFollowsForExport :many
+SELECT feeds.name AS feed_name, feeds.url, feeds.site_url, users_feeds_follows.title, folders.name AS folder_name
+FROM users_feeds_follows
+JOIN feeds ON feeds.id = users_feeds_follows.feed_id
+LEFT JOIN folders ON folders.id = users_feeds_follows.folder_id
+WHERE users_feeds_follows.user_id = $1
+ORDER BY folders.position ASC NULLS LAST, folders.name ASC, users_feeds_follows.sort_priority DESC, users_feeds_follows.created_at ASC;
@@ -0,0 +1,83 @@
+-- name: MarkPostsRead :execrows
+INSERT INTO users_posts_states(user_id, post_id, created_at, updated_at, read_at)
+SELECT sqlc.arg(user_id)::uuid, posts.id, NOW(), NOW(), NOW()
+FROM posts
+WHERE posts.id = ANY(sqlc.arg(post_ids)::uuid[])
+AND EXISTS (
+    SELECT 1 FROM users_feeds_follows
+    WHERE users_feeds_follows.user_id = sqlc.arg(user_id)
+    AND users_feeds_follows.feed_id = posts.feed_id
+)
+ON CONFLICT (user_id, post_id) DO UPDATE
+SET read_at = EXCLUDED.read_at, updated_at = EXCLUDED.updated_at
+WHERE users_posts_states.read_at IS NULL;
+
+-- name: MarkPostsUnread :execrows
+UPDATE users_posts_states
+SET read_at = NULL, updated_at = NOW()
+WHERE user_id = sqlc.arg(user_id) AND post_id = ANY(sqlc.arg(post_ids)::uuid[]) AND read_at IS NOT NULL;
+
+-- name: MarkAllPostsReadBefore :execrows
+INSERT INTO users_posts_states(user_id, post_id, created_at, updated_at, read_at)
+SELECT users_feeds_follows.user_id, posts.id, NOW(), NOW(), NOW()
+FROM posts
+JOIN users_feeds_follows ON users_feeds_follows.feed_id = posts.feed_id
+WHERE users_feeds_follows.user_id = sqlc.arg(user_id)
+AND posts.published_at < sqlc.arg(before)::timestamp
+AND (sqlc.narg(feed_id)::uuid IS NULL OR posts.feed_id = sqlc.narg(feed_id))
+AND (sqlc.narg(folder_id)::uuid IS NULL OR users_feeds_follows.folder_id = sqlc.narg(folder_id))
+ON CONFLICT (user_id, post_id) DO UPDATE
+SET read_at = EXCLUDED.read_at, updated_at = EXCLUDED.updated_at
+WHERE users_posts_states.read_at IS NULL;
+
+-- name: GetUnreadCountsByUser :many
+SELECT users_feeds_follows.feed_id, COUNT(posts.id) AS unread_count
+FROM users_feeds_follows
+JOIN posts ON posts.feed_id = users_feeds_follows.feed_id
+LEFT JOIN users_posts_states ON users_posts_states.post_id = posts.id AND users_posts_states.user_id = users_feeds_follows.user_id
+WHERE users_feeds_follows.user_id = $1 AND users_posts_states.read_at IS NULL AND users_posts_states.hidden_at IS NULL
+GROUP BY users_feeds_follows.feed_id;
+
+-- name: StarPost :execrows
+INSERT INTO users_posts_states(user_id, post_id, created_at, updated_at, starred_at)
+SELECT sqlc.arg(user_id)::uuid, posts.id, NOW(), NOW(), NOW()
+FROM posts
+WHERE posts.id = sqlc.arg(post_id)
+AND EXISTS (
+    SELECT 1 FROM users_feeds_follows
+    WHERE users_feeds_follows.user_id = sqlc.arg(user_id)
+    AND users_feeds_follows.feed_id = posts.feed_id
+)
+ON CONFLICT (user_id, post_id) DO UPDATE
+SET starred_at = EXCLUDED.starred_at, updated_at = EXCLUDED.updated_at
+WHERE users_posts_states.starred_at IS NULL;
+
+-- name: UnstarPost :execrows
+UPDATE users_posts_states
+SET starred_at = NULL, updated_at = NOW()
+WHERE user_id = $1 AND post_id = $2 AND starred_at IS NOT NULL;
+
+-- name: HidePost :execrows
+INSERT INTO users_posts_states(user_id, post_id, created_at, updated_at, hidden_at)
+SELECT sqlc.arg(user_id)::uuid, posts.id, NOW(), NOW(), NOW()
+FROM posts
+WHERE posts.id = sqlc.arg(post_id)
+AND EXISTS (
+    SELECT 1 FROM users_feeds_follows
+    WHERE users_feeds_follows.user_id = sqlc.arg(user_id)
+    AND users_feeds_follows.feed_id = posts.feed_id
+)
+ON CONFLICT (user_id, post_id) DO UPDATE
+SET hidden_at = EXCLUDED.hidden_at, updated_at = EXCLUDED.updated_at
+WHERE users_posts_states.hidden_at IS NULL;
+
+-- name: UnhidePost :execrows
+UPDATE users_posts_states
+SET hidden_at = NULL, updated_at = NOW()
+WHERE user_id = $1 AND post_id = $2 AND hidden_at IS NOT NULL;
+
+-- name: CountFollowedPosts :one
+SELECT COUNT(DISTINCT posts.id) FROM posts
+JOIN users_feeds_follows ON users_feeds_follows.feed_id = posts.feed_id
+WHERE users_feeds_follows.user_id = sqlc.arg(user_id)
+AND posts.id = ANY(sqlc.arg(post_ids)::uuid[]);
@@ -0,0 +1,30 @@
+-- +goose Up
+ALTER TABLE feeds
+ADD COLUMN canonical_url TEXT;
+
+-- Existing feeds get an approximate key (scheme, host case and trailing
+-- slashes ignored). Rows that would collide keep their raw url so the
+-- unique constraint can be added.
+UPDATE feeds SET canonical_url = url;
+
+UPDATE feeds
+SET canonical_url = keys.canonical_url
+FROM (
+    SELECT id, canonical_url, COUNT(*) OVER (PARTITION BY canonical_url) AS n
+    FROM (
+        SELECT id,
+        lower(substring(stripped FROM '^[^/?#]*')) || regexp_replace(coalesce(substring(stripped FROM '[/?#].*$'), ''), '/+$', '') AS canonical_url
+        FROM (
+            SELECT id, regexp_replace(url, '^[a-zA-Z]+://', '') AS stripped FROM feeds
+        ) s
+    ) k
+) keys
+WHERE feeds.id = keys.id AND keys.n = 1;
+
+ALTER TABLE feeds
+ALTER COLUMN canonical_url SET NOT NULL,
+ADD CONSTRAINT feeds_canonical_url_key UNIQUE(canonical_url);
+
+-- +goose Down
+ALTER TABLE feeds
+DROP COLUMN canonical_url;
@@ -0,0 +1,20 @@
+-- +goose Up
+CREATE TABLE users_posts_states(
+    user_id UUID NOT NULL,
+    CONSTRAINT fk_user_id
+    FOREIGN KEY(user_id)
+    REFERENCES users(id)
+    ON DELETE CASCADE,
+    post_id UUID NOT NULL,
+    CONSTRAINT fk_post_id
+    FOREIGN KEY(post_id)
+    REFERENCES posts(id)
+    ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    read_at TIMESTAMP DEFAULT NULL,
+    PRIMARY KEY(user_id, post_id)
+);
+
+-- +goose Down
+DROP TABLE users_posts_states;
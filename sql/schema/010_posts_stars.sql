@@ -0,0 +1,55 @@
+-- +goose Up
+ALTER TABLE users_posts_states
+ADD COLUMN starred_at TIMESTAMP DEFAULT NULL;
+
+CREATE INDEX users_posts_states_starred_idx
+ON users_posts_states(user_id, post_id)
+WHERE starred_at IS NOT NULL;
+
+-- Starred posts outlive their feed: deleting a feed only removes the posts
+-- nobody starred and detaches the rest.
+ALTER TABLE posts
+ALTER COLUMN feed_id DROP NOT NULL,
+DROP CONSTRAINT fk_feed_id,
+ADD CONSTRAINT fk_feed_id
+FOREIGN KEY(feed_id)
+REFERENCES feeds(id)
+ON DELETE SET NULL;
+
+-- +goose StatementBegin
+CREATE FUNCTION delete_unstarred_feed_posts() RETURNS trigger AS $$
+BEGIN
+    DELETE FROM posts
+    WHERE posts.feed_id = OLD.id
+    AND NOT EXISTS (
+        SELECT 1 FROM users_posts_states
+        WHERE users_posts_states.post_id = posts.id
+        AND users_posts_states.starred_at IS NOT NULL
+    );
+    RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER feeds_delete_unstarred_posts
+BEFORE DELETE ON feeds
+FOR EACH ROW EXECUTE FUNCTION delete_unstarred_feed_posts();
+
+-- +goose Down
+DROP TRIGGER feeds_delete_unstarred_posts ON feeds;
+DROP FUNCTION delete_unstarred_feed_posts;
+
+DELETE FROM posts WHERE feed_id IS NULL;
+
+ALTER TABLE posts
+DROP CONSTRAINT fk_feed_id,
+ALTER COLUMN feed_id SET NOT NULL,
+ADD CONSTRAINT fk_feed_id
+FOREIGN KEY(feed_id)
+REFERENCES feeds(id)
+ON DELETE CASCADE;
+
+DROP INDEX users_posts_states_starred_idx;
+
+ALTER TABLE users_posts_states
+DROP COLUMN starred_at;
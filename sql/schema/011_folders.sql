@@ -0,0 +1,27 @@
+-- +goose Up
+CREATE TABLE folders(
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    name TEXT NOT NULL,
+    position INTEGER NOT NULL DEFAULT 0,
+    user_id UUID NOT NULL,
+    CONSTRAINT fk_user_id
+    FOREIGN KEY(user_id)
+    REFERENCES users(id)
+    ON DELETE CASCADE,
+    UNIQUE(user_id, name)
+);
+
+ALTER TABLE users_feeds_follows
+ADD COLUMN folder_id UUID DEFAULT NULL,
+ADD CONSTRAINT fk_folder_id
+FOREIGN KEY(folder_id)
+REFERENCES folders(id)
+ON DELETE SET NULL;
+
+-- +goose Down
+ALTER TABLE users_feeds_follows
+DROP COLUMN folder_id;
+
+DROP TABLE folders;
@@ -0,0 +1,16 @@
+-- +goose Up
+ALTER TABLE users_feeds_follows
+ADD COLUMN title TEXT DEFAULT NULL,
+ADD COLUMN muted BOOLEAN NOT NULL DEFAULT FALSE,
+ADD COLUMN notifications TEXT NOT NULL DEFAULT 'none'
+CHECK (notifications IN ('none', 'all')),
+ADD COLUMN sort_priority INTEGER NOT NULL DEFAULT 0,
+ADD COLUMN hide_from_timeline BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- +goose Down
+ALTER TABLE users_feeds_follows
+DROP COLUMN title,
+DROP COLUMN muted,
+DROP COLUMN notifications,
+DROP COLUMN sort_priority,
+DROP COLUMN hide_from_timeline;
@@ -0,0 +1,17 @@
+-- +goose Up
+CREATE TABLE output_feed_tokens(
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    name TEXT NOT NULL,
+    token VARCHAR(64) UNIQUE NOT NULL,
+    revoked_at TIMESTAMP DEFAULT NULL,
+    user_id UUID NOT NULL,
+    CONSTRAINT fk_user_id
+    FOREIGN KEY(user_id)
+    REFERENCES users(id)
+    ON DELETE CASCADE
+);
+
+-- +goose Down
+DROP TABLE output_feed_tokens;
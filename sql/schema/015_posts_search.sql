@@ -0,0 +1,12 @@
+-- +goose Up
+ALTER TABLE posts
+ADD COLUMN content TEXT DEFAULT NULL;
+
+CREATE INDEX posts_search_idx ON posts
+USING GIN (to_tsvector('english', title || ' ' || coalesce(description, '') || ' ' || coalesce(content, '')));
+
+-- +goose Down
+DROP INDEX posts_search_idx;
+
+ALTER TABLE posts
+DROP COLUMN content;
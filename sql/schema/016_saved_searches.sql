@@ -0,0 +1,33 @@
+-- +goose Up
+CREATE TABLE saved_searches(
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    name TEXT NOT NULL,
+    query TEXT NOT NULL,
+    ts_query TEXT NOT NULL,
+    user_id UUID NOT NULL,
+    CONSTRAINT fk_user_id
+    FOREIGN KEY(user_id)
+    REFERENCES users(id)
+    ON DELETE CASCADE
+);
+
+CREATE TABLE saved_search_matches(
+    saved_search_id UUID NOT NULL,
+    CONSTRAINT fk_saved_search_id
+    FOREIGN KEY(saved_search_id)
+    REFERENCES saved_searches(id)
+    ON DELETE CASCADE,
+    post_id UUID NOT NULL,
+    CONSTRAINT fk_post_id
+    FOREIGN KEY(post_id)
+    REFERENCES posts(id)
+    ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL,
+    PRIMARY KEY(saved_search_id, post_id)
+);
+
+-- +goose Down
+DROP TABLE saved_search_matches;
+DROP TABLE saved_searches;
@@ -0,0 +1,86 @@
+-- +goose Up
+ALTER TABLE posts
+ADD COLUMN author TEXT DEFAULT NULL,
+ADD COLUMN categories TEXT[] NOT NULL DEFAULT '{}';
+
+ALTER TABLE users_posts_states
+ADD COLUMN hidden_at TIMESTAMP DEFAULT NULL;
+
+CREATE TABLE filter_rules(
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    name TEXT NOT NULL,
+    conditions JSONB NOT NULL,
+    match_all BOOLEAN NOT NULL DEFAULT TRUE,
+    actions TEXT[] NOT NULL,
+    tag TEXT DEFAULT NULL,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    user_id UUID NOT NULL,
+    CONSTRAINT fk_user_id
+    FOREIGN KEY(user_id)
+    REFERENCES users(id)
+    ON DELETE CASCADE
+);
+
+CREATE TABLE labels(
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    name TEXT NOT NULL,
+    user_id UUID NOT NULL,
+    CONSTRAINT fk_user_id
+    FOREIGN KEY(user_id)
+    REFERENCES users(id)
+    ON DELETE CASCADE,
+    UNIQUE(user_id, name)
+);
+
+CREATE TABLE posts_labels(
+    label_id UUID NOT NULL,
+    CONSTRAINT fk_label_id
+    FOREIGN KEY(label_id)
+    REFERENCES labels(id)
+    ON DELETE CASCADE,
+    post_id UUID NOT NULL,
+    CONSTRAINT fk_post_id
+    FOREIGN KEY(post_id)
+    REFERENCES posts(id)
+    ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL,
+    PRIMARY KEY(label_id, post_id)
+);
+
+CREATE TABLE notifications(
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    user_id UUID NOT NULL,
+    CONSTRAINT fk_user_id
+    FOREIGN KEY(user_id)
+    REFERENCES users(id)
+    ON DELETE CASCADE,
+    post_id UUID NOT NULL,
+    CONSTRAINT fk_post_id
+    FOREIGN KEY(post_id)
+    REFERENCES posts(id)
+    ON DELETE CASCADE,
+    filter_rule_id UUID DEFAULT NULL,
+    CONSTRAINT fk_filter_rule_id
+    FOREIGN KEY(filter_rule_id)
+    REFERENCES filter_rules(id)
+    ON DELETE SET NULL,
+    UNIQUE(user_id, post_id)
+);
+
+-- +goose Down
+DROP TABLE notifications;
+DROP TABLE posts_labels;
+DROP TABLE labels;
+DROP TABLE filter_rules;
+
+ALTER TABLE users_posts_states
+DROP COLUMN hidden_at;
+
+ALTER TABLE posts
+DROP COLUMN author,
+DROP COLUMN categories;
@@ -0,0 +1,40 @@
+-- +goose Up
+ALTER TABLE users
+ADD COLUMN role TEXT NOT NULL DEFAULT 'user'
+CHECK (role IN ('user', 'admin'));
+
+-- Feeds outlive their creator when somebody else follows them: ownership
+-- moves to the longest-standing other follower before the user row goes
+-- away, so only feeds nobody else follows are removed by fk_user_id.
+-- +goose StatementBegin
+CREATE FUNCTION transfer_owned_feeds() RETURNS trigger AS $$
+BEGIN
+    UPDATE feeds
+    SET user_id = (
+        SELECT users_feeds_follows.user_id FROM users_feeds_follows
+        WHERE users_feeds_follows.feed_id = feeds.id
+        AND users_feeds_follows.user_id <> OLD.id
+        ORDER BY users_feeds_follows.created_at ASC
+        LIMIT 1
+    ), updated_at = NOW()
+    WHERE feeds.user_id = OLD.id
+    AND EXISTS (
+        SELECT 1 FROM users_feeds_follows
+        WHERE users_feeds_follows.feed_id = feeds.id
+        AND users_feeds_follows.user_id <> OLD.id
+    );
+    RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER users_transfer_owned_feeds
+BEFORE DELETE ON users
+FOR EACH ROW EXECUTE FUNCTION transfer_owned_feeds();
+
+-- +goose Down
+DROP TRIGGER users_transfer_owned_feeds ON users;
+DROP FUNCTION transfer_owned_feeds;
+
+ALTER TABLE users
+DROP COLUMN role;
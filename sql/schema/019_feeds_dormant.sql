@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE feeds
+ADD COLUMN dormant_since TIMESTAMP DEFAULT NULL;
+
+-- +goose Down
+ALTER TABLE feeds
+DROP COLUMN dormant_since;
@@ -0,0 +1,11 @@
+-- +goose Up
+ALTER TABLE feeds
+ADD COLUMN retention_max_age_days INTEGER DEFAULT NULL
+CHECK (retention_max_age_days > 0),
+ADD COLUMN retention_max_posts INTEGER DEFAULT NULL
+CHECK (retention_max_posts > 0);
+
+-- +goose Down
+ALTER TABLE feeds
+DROP COLUMN retention_max_age_days,
+DROP COLUMN retention_max_posts;
@@ -0,0 +1,24 @@
+-- +goose Up
+CREATE TABLE api_keys(
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    name TEXT NOT NULL,
+    key VARCHAR(64) UNIQUE NOT NULL,
+    last_used_at TIMESTAMP DEFAULT NULL,
+    expires_at TIMESTAMP DEFAULT NULL,
+    revoked_at TIMESTAMP DEFAULT NULL,
+    user_id UUID NOT NULL,
+    CONSTRAINT fk_user_id
+    FOREIGN KEY(user_id)
+    REFERENCES users(id)
+    ON DELETE CASCADE
+);
+
+-- Existing keys keep working as each user's "default" key.
+INSERT INTO api_keys(id, created_at, updated_at, name, key, user_id)
+SELECT gen_random_uuid(), NOW(), NOW(), 'default', users.api_key, users.id
+FROM users;
+
+-- +goose Down
+DROP TABLE api_keys;
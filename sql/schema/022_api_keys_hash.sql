@@ -0,0 +1,40 @@
+-- +goose Up
+-- Keys are stored as their SHA-256 digest plus a short prefix used to look
+-- them up and to tell them apart in listings. Clients keep using the keys
+-- they hold; only the server stops knowing them.
+ALTER TABLE api_keys
+ADD COLUMN key_hash VARCHAR(64),
+ADD COLUMN prefix VARCHAR(8);
+
+UPDATE api_keys
+SET key_hash = encode(sha256(key::bytea), 'hex'), prefix = left(key, 8);
+
+ALTER TABLE api_keys
+ALTER COLUMN key_hash SET NOT NULL,
+ALTER COLUMN prefix SET NOT NULL,
+ADD CONSTRAINT api_keys_key_hash_key UNIQUE(key_hash),
+DROP COLUMN key;
+
+CREATE INDEX api_keys_prefix_idx ON api_keys(prefix);
+
+ALTER TABLE users
+DROP COLUMN api_key;
+
+-- +goose Down
+-- Plaintext keys can't be recovered: every user gets a fresh key.
+ALTER TABLE users
+ADD COLUMN api_key VARCHAR(64) UNIQUE NOT NULL
+DEFAULT(encode(sha256(random()::text::bytea), 'hex'));
+
+DROP INDEX api_keys_prefix_idx;
+
+DELETE FROM api_keys;
+
+ALTER TABLE api_keys
+ADD COLUMN key VARCHAR(64) UNIQUE NOT NULL,
+DROP COLUMN key_hash,
+DROP COLUMN prefix;
+
+INSERT INTO api_keys(id, created_at, updated_at, name, key, user_id)
+SELECT gen_random_uuid(), NOW(), NOW(), 'default', users.api_key, users.id
+FROM users;
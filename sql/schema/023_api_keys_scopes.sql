@@ -0,0 +1,17 @@
+-- +goose Up
+-- Existing keys keep full access: every user scope, plus admin for admins.
+ALTER TABLE api_keys
+ADD COLUMN scopes TEXT[] NOT NULL
+DEFAULT '{posts:read,posts:write,feeds:write,follows:read,follows:write,account:write}';
+
+UPDATE api_keys
+SET scopes = array_append(scopes, 'admin')
+FROM users
+WHERE users.id = api_keys.user_id AND users.role = 'admin';
+
+ALTER TABLE api_keys
+ALTER COLUMN scopes DROP DEFAULT;
+
+-- +goose Down
+ALTER TABLE api_keys
+DROP COLUMN scopes;
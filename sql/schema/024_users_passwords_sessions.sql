@@ -0,0 +1,32 @@
+-- +goose Up
+ALTER TABLE users
+ADD COLUMN password_hash TEXT DEFAULT NULL;
+
+-- Names only have to be unique among accounts that log in with them.
+CREATE UNIQUE INDEX users_login_name_idx ON users(lower(name))
+WHERE password_hash IS NOT NULL;
+
+CREATE TABLE sessions(
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    last_seen_at TIMESTAMP NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    token_hash VARCHAR(64) UNIQUE NOT NULL,
+    csrf_token VARCHAR(64) NOT NULL,
+    user_agent TEXT DEFAULT NULL,
+    user_id UUID NOT NULL,
+    CONSTRAINT fk_user_id
+    FOREIGN KEY(user_id)
+    REFERENCES users(id)
+    ON DELETE CASCADE
+);
+
+CREATE INDEX sessions_user_id_idx ON sessions(user_id);
+
+-- +goose Down
+DROP TABLE sessions;
+
+DROP INDEX users_login_name_idx;
+
+ALTER TABLE users
+DROP COLUMN password_hash;
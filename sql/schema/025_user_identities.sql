@@ -0,0 +1,18 @@
+-- +goose Up
+-- Links users to the accounts they log in with at an OpenID Connect
+-- provider, identified by the provider's issuer and its subject identifier.
+CREATE TABLE user_identities(
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    issuer TEXT NOT NULL,
+    subject TEXT NOT NULL,
+    user_id UUID NOT NULL,
+    CONSTRAINT fk_user_id
+    FOREIGN KEY(user_id)
+    REFERENCES users(id)
+    ON DELETE CASCADE,
+    UNIQUE(issuer, subject)
+);
+
+-- +goose Down
+DROP TABLE user_identities;
@@ -0,0 +1,19 @@
+-- +goose Up
+CREATE TABLE invites(
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    code TEXT UNIQUE NOT NULL,
+    max_uses INTEGER NOT NULL CHECK (max_uses > 0),
+    uses INTEGER NOT NULL DEFAULT 0,
+    expires_at TIMESTAMP DEFAULT NULL,
+    revoked_at TIMESTAMP DEFAULT NULL,
+    created_by UUID DEFAULT NULL,
+    CONSTRAINT fk_created_by
+    FOREIGN KEY(created_by)
+    REFERENCES users(id)
+    ON DELETE SET NULL
+);
+
+-- +goose Down
+DROP TABLE invites;
@@ -0,0 +1,14 @@
+-- +goose Up
+CREATE TABLE pruned_posts(
+    feed_id UUID NOT NULL,
+    CONSTRAINT fk_feed_id
+    FOREIGN KEY(feed_id)
+    REFERENCES feeds(id)
+    ON DELETE CASCADE,
+    url TEXT NOT NULL,
+    pruned_at TIMESTAMP NOT NULL,
+    PRIMARY KEY(feed_id, url)
+);
+
+-- +goose Down
+DROP TABLE pruned_posts;
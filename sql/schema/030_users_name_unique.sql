@@ -0,0 +1,21 @@
+-- +goose Up
+-- Names were only unique among password accounts. Duplicates other than the
+-- password account, or else the oldest account, get the start of their id
+-- appended before every name is made unique.
+UPDATE users
+SET name = users.name || '-' || left(users.id::text, 8), updated_at = NOW()
+FROM (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY lower(name) ORDER BY password_hash IS NULL, created_at, id) AS position
+    FROM users
+) AS ranked
+WHERE ranked.id = users.id AND ranked.position > 1;
+
+DROP INDEX users_login_name_idx;
+
+CREATE UNIQUE INDEX users_name_idx ON users(lower(name));
+
+-- +goose Down
+DROP INDEX users_name_idx;
+
+CREATE UNIQUE INDEX users_login_name_idx ON users(lower(name))
+WHERE password_hash IS NOT NULL;
@@ -0,0 +1,24 @@
+-- +goose Up
+-- 'rules' only notifies about posts a notify filter rule matches, and is the
+-- new default. Follows left at 'none' get it too: until now 'none' was the
+-- only setting that didn't notify about every post.
+ALTER TABLE users_feeds_follows
+DROP CONSTRAINT users_feeds_follows_notifications_check,
+ADD CONSTRAINT users_feeds_follows_notifications_check
+CHECK (notifications IN ('none', 'rules', 'all')),
+ALTER COLUMN notifications SET DEFAULT 'rules';
+
+UPDATE users_feeds_follows
+SET notifications = 'rules'
+WHERE notifications = 'none';
+
+-- +goose Down
+UPDATE users_feeds_follows
+SET notifications = 'none'
+WHERE notifications = 'rules';
+
+ALTER TABLE users_feeds_follows
+DROP CONSTRAINT users_feeds_follows_notifications_check,
+ADD CONSTRAINT users_feeds_follows_notifications_check
+CHECK (notifications IN ('none', 'all')),
+ALTER COLUMN notifications SET DEFAULT 'none';
@@ -0,0 +1,224 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/JustinLi007/rss-aggregator/internal/database"
+	"github.com/JustinLi007/rss-aggregator/internal/oidc"
+	"github.com/google/uuid"
+)
+
+// ssoConfig configures logging in through an OpenID Connect provider.
+type ssoConfig struct {
+	provider *oidc.Provider
+	// adminClaim and adminValues map provider claims to the admin role,
+	// e.g. a "groups" claim containing "rss-admins". With no adminClaim,
+	// roles are managed here and left alone on login.
+	adminClaim  string
+	adminValues []string
+	// postLoginURL is where the browser is sent once logged in.
+	postLoginURL string
+}
+
+// loadSSOConfig reads the OIDC_* environment variables and discovers the
+// provider. It returns nil when single sign-on isn't configured.
+func loadSSOConfig(ctx context.Context) (*ssoConfig, error) {
+	issuerURL := os.Getenv("OIDC_ISSUER_URL")
+	if issuerURL == "" {
+		return nil, nil
+	}
+
+	config := oidc.Config{
+		IssuerURL:    issuerURL,
+		ClientID:     os.Getenv("OIDC_CLIENT_ID"),
+		ClientSecret: os.Getenv("OIDC_CLIENT_SECRET"),
+		RedirectURL:  os.Getenv("OIDC_REDIRECT_URL"),
+		Scopes:       []string{"profile", "email"},
+	}
+	if config.ClientID == "" || config.RedirectURL == "" {
+		return nil, errors.New("OIDC_CLIENT_ID and OIDC_REDIRECT_URL must be set with OIDC_ISSUER_URL")
+	}
+	if value := os.Getenv("OIDC_SCOPES"); value != "" {
+		config.Scopes = strings.Fields(value)
+	}
+
+	provider, err := oidc.Discover(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+
+	sso := &ssoConfig{
+		provider:     provider,
+		adminClaim:   os.Getenv("OIDC_ADMIN_CLAIM"),
+		postLoginURL: os.Getenv("OIDC_POST_LOGIN_URL"),
+	}
+	for _, value := range strings.Split(os.Getenv("OIDC_ADMIN_VALUES"), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			sso.adminValues = append(sso.adminValues, value)
+		}
+	}
+	if sso.adminClaim != "" && len(sso.adminValues) == 0 {
+		return nil, fmt.Errorf("OIDC_ADMIN_VALUES must be set with OIDC_ADMIN_CLAIM")
+	}
+	if sso.postLoginURL == "" {
+		sso.postLoginURL = "/"
+	}
+	return sso, nil
+}
+
+// role returns the role claims grant, and false when roles aren't mapped
+// from claims.
+func (sso *ssoConfig) role(claims *oidc.Claims) (string, bool) {
+	if sso.adminClaim == "" {
+		return "", false
+	}
+	for _, value := range sso.adminValues {
+		if claims.HasValue(sso.adminClaim, value) {
+			return roleAdmin, true
+		}
+	}
+	return roleUser, true
+}
+
+// ssoProvisionAttempts bounds how often creating a user on first login is
+// retried after losing a race for its name.
+const ssoProvisionAttempts = 3
+
+var (
+	errRegistrationClosed = errors.New("Registration is closed")
+	errInvalidInvite      = errors.New("Invalid or expired invite code")
+)
+
+// ssoUserName picks the name of a user created on first login.
+func ssoUserName(claims *oidc.Claims) string {
+	for _, name := range []string{claims.PreferredUsername, claims.Name, claims.Email} {
+		if name = strings.TrimSpace(name); name != "" {
+			return truncateUserName(name)
+		}
+	}
+	return truncateUserName(claims.Subject)
+}
+
+func truncateUserName(name string) string {
+	if runes := []rune(name); len(runes) > maxUserNameLength {
+		return string(runes[:maxUserNameLength])
+	}
+	return name
+}
+
+// uniqueUserName returns name, or name with the first free numeric suffix
+// when another user has it already.
+func uniqueUserName(ctx context.Context, db *database.Queries, name string) (string, error) {
+	candidate := name
+	for n := 2; ; n++ {
+		exists, err := db.UserNameExists(ctx, candidate)
+		if err != nil {
+			return "", err
+		}
+		if !exists {
+			return candidate, nil
+		}
+		candidate = fmt.Sprintf("%v-%v", name, n)
+	}
+}
+
+// provisionSSOUser returns the user linked to the identity in claims,
+// creating it on first login, and brings its role in line with the claims.
+// New users are subject to the registration mode like sign ups through
+// POST /v1/users; inviteCode is required in invite-only mode.
+func (cfg *apiConfig) provisionSSOUser(ctx context.Context, claims *oidc.Claims, inviteCode string) (database.User, error) {
+	role, mapped := cfg.SSO.role(claims)
+	if !mapped {
+		role = roleUser
+	}
+
+	user, err := cfg.DB.GetUserByIdentity(ctx, database.GetUserByIdentityParams{
+		Issuer:  claims.Issuer,
+		Subject: claims.Subject,
+	})
+	for attempt := 0; errors.Is(err, sql.ErrNoRows) && attempt < ssoProvisionAttempts; attempt++ {
+		user, err = cfg.createSSOUser(ctx, claims, role, inviteCode)
+		if err == nil {
+			return user, nil
+		}
+		// A concurrent first login created the user already, or another
+		// user took the name picked for it.
+		if !isDuplicateKeyError(err) {
+			return database.User{}, err
+		}
+		user, err = cfg.DB.GetUserByIdentity(ctx, database.GetUserByIdentityParams{
+			Issuer:  claims.Issuer,
+			Subject: claims.Subject,
+		})
+	}
+	if err != nil {
+		return database.User{}, err
+	}
+
+	if mapped && user.Role != role {
+		if err := cfg.DB.SetUserRole(ctx, database.SetUserRoleParams{
+			ID:   user.ID,
+			Role: role,
+		}); err != nil {
+			return database.User{}, err
+		}
+		user.Role = role
+	}
+	return user, nil
+}
+
+// createSSOUser creates the user for a first login, using up an invite in
+// invite-only mode.
+func (cfg *apiConfig) createSSOUser(ctx context.Context, claims *oidc.Claims, role, inviteCode string) (database.User, error) {
+	if cfg.RegistrationMode == registrationClosed {
+		return database.User{}, errRegistrationClosed
+	}
+
+	var user database.User
+	err := cfg.withTx(ctx, func(db *database.Queries) error {
+		if cfg.RegistrationMode == registrationInvite {
+			if _, err := db.UseInvite(ctx, inviteCode); err != nil {
+				if errors.Is(err, sql.ErrNoRows) {
+					return errInvalidInvite
+				}
+				return err
+			}
+		}
+
+		name, err := uniqueUserName(ctx, db, ssoUserName(claims))
+		if err != nil {
+			return err
+		}
+		user, err = db.CreateUser(ctx, database.CreateUserParams{
+			ID:        uuid.New(),
+			CreatedAt: time.Now().UTC(),
+			UpdatedAt: time.Now().UTC(),
+			Name:      name,
+		})
+		if err != nil {
+			return err
+		}
+		if err := db.SetUserRole(ctx, database.SetUserRoleParams{
+			ID:   user.ID,
+			Role: role,
+		}); err != nil {
+			return err
+		}
+		user.Role = role
+		err = db.CreateUserIdentity(ctx, database.CreateUserIdentityParams{
+			ID:        uuid.New(),
+			CreatedAt: time.Now().UTC(),
+			Issuer:    claims.Issuer,
+			Subject:   claims.Subject,
+			UserID:    user.ID,
+		})
+		return err
+	})
+	return user, err
+}